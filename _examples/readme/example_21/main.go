@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/go-andiamo/csvamp"
+	"os"
+)
+
+type Record struct {
+	FirstName string `csv:"First name"`
+	LastName  string `csv:"Last name"`
+	Age       int    `csv:"Age"`
+}
+
+var mapper = csvamp.MustNewMapper[Record]()
+
+func main() {
+	recs := []Record{
+		{FirstName: "Frodo", LastName: "Baggins", Age: 50},
+		{FirstName: "Samwise", LastName: "Gamgee", Age: 38},
+		{FirstName: "Aragorn", LastName: "Elessar", Age: 87},
+	}
+
+	w := mapper.Writer(os.Stdout)
+	if err := w.WriteAll(recs); err != nil {
+		panic(err)
+	}
+}
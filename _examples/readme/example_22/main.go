@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-andiamo/csvamp"
+	"strings"
+	"time"
+)
+
+type Record struct {
+	FirstName string
+	LastName  string
+	DOB       time.Time  `csv:",layout=02/01/2006"`
+	Updated   *time.Time `csv:",layout=2006-01-02 15:04,tz=Europe/London"`
+}
+
+var mapper = csvamp.MustNewMapper[Record]()
+
+func main() {
+	const data = `First name,Last name,Date Of Birth,Updated
+Frodo,Baggins,22/09/2968,3018-09-23 10:30
+Samwise,Gamgee,06/04/2980,
+Aragorn,Elessar,01/03/2931,3019-03-25 12:00`
+
+	r := mapper.Reader(strings.NewReader(data), nil)
+	err := r.Iterate(func(record Record) (bool, error) {
+		fmt.Printf("Name: %s %s\n", record.FirstName, record.LastName)
+		fmt.Printf(" DOB: %s\n", record.DOB.Format("Mon, 02 Jan 2006"))
+		if record.Updated != nil {
+			fmt.Printf(" Updated: %s\n", record.Updated.Format(time.RFC3339))
+		}
+		return true, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
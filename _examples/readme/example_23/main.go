@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"github.com/go-andiamo/csvamp"
+	"strings"
+)
+
+type Record struct {
+	Name  string `csv:"'Surname, Forename',trim,required"`
+	Age   int    `csv:"Age,trim,default=18"`
+	Email string `csv:"Email,trim,default=unknown"`
+}
+
+var mapper = csvamp.MustNewMapper[Record]()
+
+func main() {
+	const data = `"Surname, Forename",Age,Email
+"  Baggins, Frodo ", 50 ,frodo@example.com
+"Gamgee, Samwise",,
+"   ",33,`
+
+	r := mapper.Reader(strings.NewReader(data), nil)
+	for {
+		record, err := r.Read()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			break
+		}
+		fmt.Printf("Name: %q, Age: %d, Email: %s\n", record.Name, record.Age, record.Email)
+	}
+}
@@ -0,0 +1,128 @@
+package csvamp
+
+import (
+	"fmt"
+	"github.com/go-andiamo/csvamp/csv"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+var bigFloatType = reflect.TypeOf(big.Float{})
+
+var bigRatType = reflect.TypeOf(big.Rat{})
+
+// isBigType determines whether the type is one of the natively supported math/big types (which are text marshalers/unmarshalers - but are handled natively)
+func isBigType(t reflect.Type) bool {
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+func setterBigInt(base *int, nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		bi := v.Addr().Interface().(*big.Int)
+		if defEmpties && val == "" {
+			bi.SetInt64(0)
+		} else if _, ok := bi.SetString(parseInteger(val, base, nf), baseOf(base)); !ok {
+			return fmt.Errorf("cannot convert value %q to big.Int", val)
+		}
+		return nil
+	}
+}
+
+func setterBigFloat(prec uint, nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		bf := v.Addr().Interface().(*big.Float)
+		if defEmpties && val == "" {
+			bf.SetInt64(0)
+			return nil
+		}
+		s := parseFloat(val, nf)
+		p := prec
+		if p == 0 {
+			p = bigFloatPrecision(s)
+		}
+		f, _, err := big.ParseFloat(s, 10, p, big.ToNearestEven)
+		if err != nil {
+			return fmt.Errorf("cannot convert value %q to big.Float", val)
+		}
+		bf.Set(f)
+		return nil
+	}
+}
+
+// bigFloatPrecision determines the precision (in mantissa bits) required to represent the decimal digits of a value exactly (minimum 64 bits)
+func bigFloatPrecision(s string) uint {
+	digits := 0
+	for _, r := range s {
+		if r == 'e' || r == 'E' {
+			break
+		} else if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return max(64, uint(math.Ceil(float64(digits)*math.Log2(10)))+1)
+}
+
+func setterBigRat(nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		br := v.Addr().Interface().(*big.Rat)
+		if defEmpties && val == "" {
+			br.SetInt64(0)
+		} else if _, ok := br.SetString(parseFloat(val, nf)); !ok {
+			return fmt.Errorf("cannot convert value %q to big.Rat", val)
+		}
+		return nil
+	}
+}
+
+func setterComplex(bitSize int) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if defEmpties && val == "" {
+			v.SetComplex(0)
+		} else if c, err := strconv.ParseComplex(val, bitSize); err != nil {
+			return conversionError(val, "complex"+strconv.Itoa(bitSize), err)
+		} else {
+			v.SetComplex(c)
+		}
+		return nil
+	}
+}
+
+func getterBigInt(intBase *int, nf *NumberFormat) valueGetter {
+	base := baseOf(intBase)
+	if base == 0 {
+		base = 10
+	}
+	if nf != nil {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return nf.formatInteger(addressOf(v).Interface().(*big.Int).Text(base)), csv.QuoteDefault, nil
+		}
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return addressOf(v).Interface().(*big.Int).Text(base), csv.QuoteDefault, nil
+	}
+}
+
+func getterBigFloat(nf *NumberFormat) valueGetter {
+	if nf != nil {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return nf.formatFloat(addressOf(v).Interface().(*big.Float).Text('f', -1)), csv.QuoteDefault, nil
+		}
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return addressOf(v).Interface().(*big.Float).Text('f', -1), csv.QuoteDefault, nil
+	}
+}
+
+func getterBigRat(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+	return addressOf(v).Interface().(*big.Rat).RatString(), csv.QuoteDefault, nil
+}
+
+func getterComplex(bitSize int) valueGetter {
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return strconv.FormatComplex(v.Complex(), 'g', -1, bitSize), csv.QuoteDefault, nil
+	}
+}
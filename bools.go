@@ -0,0 +1,64 @@
+package csvamp
+
+import (
+	"strings"
+)
+
+var (
+	defaultTrueValues  = []string{"1", "t", "true"}
+	defaultFalseValues = []string{"0", "f", "false"}
+)
+
+// boolTokens are the resolved (lower-cased) tokens for reading and writing bool fields
+type boolTokens struct {
+	values     map[string]bool
+	trueValue  string
+	falseValue string
+}
+
+// tokens resolves the bool values tokens (nil if bools are read and written as per strconv)
+func (bv *BoolValues) tokens() *boolTokens {
+	if bv == nil || (len(bv.True) == 0 && len(bv.False) == 0) {
+		return nil
+	}
+	trues, falses := bv.True, bv.False
+	if len(trues) == 0 {
+		trues = defaultTrueValues
+	}
+	if len(falses) == 0 {
+		falses = defaultFalseValues
+	}
+	result := &boolTokens{
+		values:     make(map[string]bool, len(trues)+len(falses)),
+		trueValue:  trues[0],
+		falseValue: falses[0],
+	}
+	for _, s := range falses {
+		result.values[strings.ToLower(s)] = false
+	}
+	for _, s := range trues {
+		result.values[strings.ToLower(s)] = true
+	}
+	return result
+}
+
+func (bt *boolTokens) parse(val string) (b bool, ok bool) {
+	b, ok = bt.values[strings.ToLower(val)]
+	return b, ok
+}
+
+// emptyIsValue determines whether an empty value is a token (i.e. is read as true or false rather than as an empty value)
+func (bt *boolTokens) emptyIsValue() bool {
+	if bt == nil {
+		return false
+	}
+	_, ok := bt.values[""]
+	return ok
+}
+
+func (bt *boolTokens) format(b bool) string {
+	if b {
+		return bt.trueValue
+	}
+	return bt.falseValue
+}
@@ -0,0 +1,301 @@
+package csvamp
+
+import (
+	"fmt"
+	"github.com/go-andiamo/csvamp/csv"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// csvColumn is a CSV field (and its header) used when setting a struct field from multiple CSV fields
+type csvColumn struct {
+	index  int
+	header string
+	value  string
+	quoted bool
+}
+
+// isEmpty determines whether the CSV field value is empty (or only whitespace, when trimmed) or a null token
+func (c csvColumn) isEmpty(opts *fieldOptions) bool {
+	if opts == nil {
+		return c.value == ""
+	} else if opts.trim {
+		return strings.TrimSpace(c.value) == "" || opts.isNull(strings.TrimSpace(c.value))
+	}
+	return c.value == "" || opts.isNull(c.value)
+}
+
+// fieldError returns the *FieldError for a CSV field value that cannot be set (the struct field path is added by the mapper)
+func (c csvColumn) fieldError(typ reflect.Type, err error) error {
+	return &FieldError{Index: c.index + 1, Header: c.header, Value: c.value, Type: typ, Err: err}
+}
+
+// anyNonEmpty determines whether any of the CSV field values is non-empty (see csvColumn.isEmpty)
+func anyNonEmpty(columns []csvColumn, opts *fieldOptions) bool {
+	for _, col := range columns {
+		if !col.isEmpty(opts) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildColumnsSetter builds a setter for a slice or map struct field that is set from multiple CSV fields
+//
+// slice fields are set with the CSV field values (in CSV order, optionally dropping empty trailing values) - map fields are set with the CSV header as key
+//
+// empty CSV field values are optionally omitted (using the "omitempty" tag option)
+func buildColumnsSetter[T any](currentPath []int, fld reflect.StructField, opts *fieldOptions) (func(t *T, columns []csvColumn, defEmpties bool, record []string) error, error) {
+	omitEmpty := opts != nil && opts.omitEmpty
+	switch fld.Type.Kind() {
+	case reflect.Slice:
+		es, err := buildValueSetter(fld.Type.Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+		es = optionsValueSetter(es, opts)
+		dropTrailing := opts != nil && opts.dropTrailing
+		return func(t *T, columns []csvColumn, defEmpties bool, record []string) error {
+			if dropTrailing {
+				for len(columns) > 0 && columns[len(columns)-1].isEmpty(opts) {
+					columns = columns[:len(columns)-1]
+				}
+			}
+			sv := reflect.MakeSlice(fld.Type, 0, len(columns))
+			for _, col := range columns {
+				if omitEmpty && col.isEmpty(opts) {
+					continue
+				}
+				ev := reflect.New(fld.Type.Elem()).Elem()
+				if err := es(ev, col.value, col.quoted, defEmpties, record); err != nil {
+					return col.fieldError(fld.Type.Elem(), err)
+				}
+				sv = reflect.Append(sv, ev)
+			}
+			// pointers to nested structs have already been allocated (if any of their mapped csv fields are non-empty)...
+			if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, false); ok {
+				v.Set(sv)
+			}
+			return nil
+		}, nil
+	case reflect.Map:
+		if opts != nil && opts.dropTrailing {
+			return nil, fmt.Errorf("csv tag option %q only supported for slice fields", csvTagOptionDropTrailing)
+		} else if fld.Type.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("struct field unsupported map key type: %s", fld.Type.Key().Kind().String())
+		}
+		es, err := buildValueSetter(fld.Type.Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+		es = optionsValueSetter(es, opts)
+		return func(t *T, columns []csvColumn, defEmpties bool, record []string) error {
+			mv := reflect.MakeMapWithSize(fld.Type, len(columns))
+			for _, col := range columns {
+				if omitEmpty && col.isEmpty(opts) {
+					continue
+				}
+				ev := reflect.New(fld.Type.Elem()).Elem()
+				if err := es(ev, col.value, col.quoted, defEmpties, record); err != nil {
+					return col.fieldError(fld.Type.Elem(), err)
+				}
+				mv.SetMapIndex(reflect.ValueOf(col.header).Convert(fld.Type.Key()), ev)
+			}
+			if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, false); ok {
+				v.Set(mv)
+			}
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("struct field unsupported type for multiple csv fields: %s", fld.Type.Kind().String())
+}
+
+// buildColumnsGetter builds the columns getter for a slice or map struct field that is written to multiple CSV fields
+//
+// the columns are resolved from the first struct written - slice fields are written as the number of CSV fields (or, if zero, the number of values in the first struct)
+// and map fields are written with the (sorted) keys of the first struct as headers
+//
+// the returned check reports any values of a struct that do not fit the resolved columns (rather than silently not writing them)
+func buildColumnsGetter[T any](currentPath []int, fld reflect.StructField, opts *fieldOptions, header string, size int) (func(first *T) ([]writerColumn[T], func(t *T) error), error) {
+	eg, err := buildValueGetter(fld.Type.Elem(), opts)
+	if err != nil {
+		return nil, err
+	}
+	fieldValue := func(t *T) (reflect.Value, bool) {
+		return fieldByPath(reflect.ValueOf(t).Elem(), currentPath, false)
+	}
+	switch fld.Type.Kind() {
+	case reflect.Slice:
+		return func(first *T) ([]writerColumn[T], func(t *T) error) {
+			n := size
+			if v, ok := fieldValue(first); ok && n == 0 {
+				n = v.Len()
+			}
+			columns := make([]writerColumn[T], n)
+			for i := range columns {
+				columns[i] = writerColumn[T]{
+					header: fmt.Sprintf("%s[%d]", header, i),
+					getter: func(t *T, record []string) (string, csv.QuotePolicy, error) {
+						if v, ok := fieldValue(t); ok && i < v.Len() {
+							return eg(v.Index(i), record)
+						}
+						return "", csv.QuoteDefault, nil
+					},
+				}
+			}
+			return columns, func(t *T) error {
+				if v, ok := fieldValue(t); ok && v.Len() > n {
+					return fmt.Errorf("slice length %d exceeds the %d csv fields written", v.Len(), n)
+				}
+				return nil
+			}
+		}, nil
+	case reflect.Map:
+		if fld.Type.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("struct field unsupported map key type: %s", fld.Type.Key().Kind().String())
+		}
+		return func(first *T) ([]writerColumn[T], func(t *T) error) {
+			var keys []string
+			if v, ok := fieldValue(first); ok {
+				keys = sortedMapKeys(v)
+			}
+			if size != 0 && len(keys) > size {
+				keys = keys[:size]
+			}
+			written := make(map[string]bool, len(keys))
+			columns := make([]writerColumn[T], len(keys))
+			for i, k := range keys {
+				written[k] = true
+				key := reflect.ValueOf(k).Convert(fld.Type.Key())
+				columns[i] = writerColumn[T]{
+					header: k,
+					getter: func(t *T, record []string) (string, csv.QuotePolicy, error) {
+						if v, ok := fieldValue(t); ok {
+							if ev := v.MapIndex(key); ev.IsValid() {
+								return eg(ev, record)
+							}
+						}
+						return "", csv.QuoteDefault, nil
+					},
+				}
+			}
+			return columns, func(t *T) error {
+				if v, ok := fieldValue(t); ok {
+					for _, k := range sortedMapKeys(v) {
+						if !written[k] {
+							return fmt.Errorf("map key %q is not a csv header written", k)
+						}
+					}
+				}
+				return nil
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("struct field unsupported type for multiple csv fields: %s", fld.Type.Kind().String())
+}
+
+// sortedMapKeys returns the (string) keys of the map value in sorted order
+func sortedMapKeys(v reflect.Value) []string {
+	result := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		result = append(result, k.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+// indexRange is a (1 based, inclusive) range of CSV field indices referenced by a struct field (e.g. `csv:"[5:12]"`) - a zero upper bound denotes all remaining CSV fields (e.g. `csv:"[5:]"`)
+type indexRange struct {
+	from int
+	to   int
+}
+
+// csvFieldRange is a slice (or map) struct field set from a range of CSV fields - map fields are keyed by CSV header (so require headers)
+type csvFieldRange[T any] struct {
+	indexRange
+	setter   func(t *T, columns []csvColumn, defEmpties bool, record []string) error
+	byHeader bool
+}
+
+// indexRangeSeparator separates the bounds of an index range referenced by a struct field (e.g. `csv:"[5:12]"`)
+const indexRangeSeparator = ":"
+
+func isIndexRange(name string) bool {
+	return strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") && strings.Contains(name, indexRangeSeparator)
+}
+
+func parseIndexRange(name string) (indexRange, error) {
+	from, to, _ := strings.Cut(name[1:len(name)-1], indexRangeSeparator)
+	result := indexRange{}
+	var err error
+	if result.from, err = strconv.Atoi(from); err != nil || result.from < 1 {
+		return result, fmt.Errorf("invalid csv field index range %s", name)
+	}
+	if to != "" {
+		if result.to, err = strconv.Atoi(to); err != nil || result.to < result.from {
+			return result, fmt.Errorf("invalid csv field index range %s", name)
+		}
+	}
+	return result, nil
+}
+
+// contains determines whether the (1 based) CSV field index is within the range
+func (r indexRange) contains(idx int) bool {
+	return idx >= r.from && (r.to == 0 || idx <= r.to)
+}
+
+// overlaps determines whether any CSV field index is within both ranges
+func (r indexRange) overlaps(other indexRange) bool {
+	return r.contains(other.from) || other.contains(r.from)
+}
+
+// columns returns the CSV fields (and their headers, if any) within the range (in CSV order)
+func (r indexRange) columns(record []string, headers []string, quoted func(i int) bool) []csvColumn {
+	to := len(record)
+	if r.to != 0 && r.to < to {
+		to = r.to
+	}
+	result := make([]csvColumn, 0, max(0, to-r.from+1))
+	for i := r.from - 1; i < to; i++ {
+		col := csvColumn{index: i, value: record[i], quoted: quoted(i)}
+		if i < len(headers) {
+			col.header = headers[i]
+		}
+		result = append(result, col)
+	}
+	return result
+}
+
+// buildExtraMapper builds the mapper for a struct field tagged with "[extra]" - the field must be a map of string (header) or int (index) to string
+func buildExtraMapper[T any](currentPath []int, fld reflect.StructField) (mapper func(t *T, extras []csvColumn), byHeader bool, ok bool) {
+	if fld.Type.Kind() != reflect.Map || fld.Type.Elem().Kind() != reflect.String {
+		return nil, false, false
+	}
+	kt, et := fld.Type.Key(), fld.Type.Elem()
+	switch kt.Kind() {
+	case reflect.String:
+		return func(t *T, extras []csvColumn) {
+			mv := reflect.MakeMapWithSize(fld.Type, len(extras))
+			for _, col := range extras {
+				mv.SetMapIndex(reflect.ValueOf(col.header).Convert(kt), reflect.ValueOf(col.value).Convert(et))
+			}
+			if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, anyNonEmpty(extras, nil)); ok {
+				v.Set(mv)
+			}
+		}, true, true
+	case reflect.Int:
+		return func(t *T, extras []csvColumn) {
+			mv := reflect.MakeMapWithSize(fld.Type, len(extras))
+			for _, col := range extras {
+				// keys are 1 based (as per csv field index tags)...
+				mv.SetMapIndex(reflect.ValueOf(col.index+1).Convert(kt), reflect.ValueOf(col.value).Convert(et))
+			}
+			if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, anyNonEmpty(extras, nil)); ok {
+				v.Set(mv)
+			}
+		}, false, true
+	}
+	return nil, false, false
+}
@@ -0,0 +1,134 @@
+package csvamp
+
+import (
+	"fmt"
+	"github.com/go-andiamo/csvamp/csv"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Converter is an option that can be passed to NewMapper / MustNewMapper - use WithConverter to create a Converter
+//
+// it registers a converter for reading fields of a specific type (and pointers to that type) - converters take precedence over the built-in types
+// (and over CsvUnmarshaler, CsvQuotedUnmarshaler or encoding.TextUnmarshaler implementations)
+type Converter struct {
+	typ    reflect.Type
+	setter valueSetter
+}
+
+// WithConverter creates a Converter option for the generic type
+//
+// Example:
+//
+//	m, err := csvamp.NewMapper[MyStruct](csvamp.WithConverter[decimal.Decimal](func(val string, quoted bool, record []string) (decimal.Decimal, error) {
+//		return decimal.NewFromString(val)
+//	}))
+func WithConverter[T any](fn func(val string, quoted bool, record []string) (T, error)) Converter {
+	return Converter{
+		typ: reflect.TypeOf((*T)(nil)).Elem(),
+		setter: func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+			t, err := fn(val, quoted, record)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(&t).Elem())
+			return nil
+		},
+	}
+}
+
+// Formatter is an option that can be passed to NewMapper / MustNewMapper - use WithFormatter to create a Formatter
+//
+// it registers a formatter for writing fields of a specific type (and pointers to that type) - formatters take precedence over the built-in types
+// (and over CsvMarshaler, CsvQuotedMarshaler or encoding.TextMarshaler implementations)
+type Formatter struct {
+	typ    reflect.Type
+	getter valueGetter
+}
+
+// WithFormatter creates a Formatter option for the generic type
+//
+// Example:
+//
+//	m, err := csvamp.NewMapper[MyStruct](csvamp.WithFormatter[decimal.Decimal](func(v decimal.Decimal, record []string) (string, error) {
+//		return v.StringFixed(2), nil
+//	}))
+func WithFormatter[T any](fn func(v T, record []string) (string, error)) Formatter {
+	return Formatter{
+		typ: reflect.TypeOf((*T)(nil)).Elem(),
+		getter: func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			val, err := fn(v.Interface().(T), record)
+			return val, csv.QuoteDefault, err
+		},
+	}
+}
+
+// Enum is an option that can be passed to NewMapper / MustNewMapper - use WithEnum to create an Enum
+//
+// it registers the allowed values for a type (e.g. `type Status string`) - reading a value that is not allowed is an error
+//
+// the allowed values can also be set per field using the csv tag option "enum" - where values are separated by "|" (e.g. `csv:"Status,enum=active|inactive|pending"`)
+type Enum struct {
+	typ    reflect.Type
+	values []string
+}
+
+// WithEnum creates an Enum option for the generic type
+//
+// Example:
+//
+//	m, err := csvamp.NewMapper[MyStruct](csvamp.WithEnum[Status](StatusActive, StatusInactive, StatusPending))
+func WithEnum[T any](values ...T) Enum {
+	result := Enum{
+		typ:    reflect.TypeOf((*T)(nil)).Elem(),
+		values: make([]string, len(values)),
+	}
+	for i, v := range values {
+		result.values[i] = enumValueString(reflect.ValueOf(v))
+	}
+	return result
+}
+
+// enumValueString formats an enum value as read from CSV - i.e. using its underlying kind (rather than any String method, e.g. of an int enum with names)
+func enumValueString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// enumValueSetter wraps the value setter with a check that the (non-empty) value is one of the allowed values
+func enumValueSetter(vs valueSetter, allowed []string) valueSetter {
+	values := make(map[string]bool, len(allowed))
+	quotedValues := make([]string, len(allowed))
+	for i, v := range allowed {
+		values[v] = true
+		quotedValues[i] = strconv.Quote(v)
+	}
+	oneOf := strings.Join(quotedValues, ", ")
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if val != "" && !values[val] {
+			return fmt.Errorf("value %q is not one of %s", val, oneOf)
+		}
+		return vs(v, val, quoted, defEmpties, record)
+	}
+}
+
+// isConvertedType determines whether the type (or pointer to type) has a registered converter or formatter - so must not be treated as a nested struct
+func (m *mapper[T]) isConvertedType(typ reflect.Type) bool {
+	if _, ok := m.converters[typ]; ok {
+		return true
+	}
+	_, ok := m.formatters[typ]
+	return ok
+}
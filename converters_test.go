@@ -0,0 +1,170 @@
+package csvamp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testMoney struct {
+	cents int64
+}
+
+func TestMapper_Converters(t *testing.T) {
+	type testStruct struct {
+		Price    testMoney     `csv:"Price"`
+		Discount *testMoney    `csv:"Discount"`
+		Prices   []testMoney   `csv:"Prices,sep=;"`
+		Timeout  time.Duration `csv:"Timeout"`
+	}
+	parseMoney := WithConverter[testMoney](func(val string, quoted bool, record []string) (testMoney, error) {
+		units, cents, _ := strings.Cut(strings.TrimPrefix(val, "$"), ".")
+		u, err := strconv.ParseInt(units+cents, 10, 64)
+		if err != nil {
+			return testMoney{}, errors.New("invalid money")
+		}
+		return testMoney{cents: u}, nil
+	})
+	formatMoney := WithFormatter[testMoney](func(v testMoney, record []string) (string, error) {
+		return fmt.Sprintf("$%d.%02d", v.cents/100, v.cents%100), nil
+	})
+	parseSeconds := WithConverter[time.Duration](func(val string, quoted bool, record []string) (time.Duration, error) {
+		secs, err := strconv.Atoi(val)
+		return time.Duration(secs) * time.Second, err
+	})
+	m, err := NewMapper[testStruct](parseMoney, formatMoney, parseSeconds)
+	require.NoError(t, err)
+
+	const data = `Price,Discount,Prices,Timeout
+$12.50,,$1.00;$2.25,30`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, testMoney{cents: 1250}, row.Price)
+	require.Nil(t, row.Discount)
+	require.Equal(t, []testMoney{{cents: 100}, {cents: 225}}, row.Prices)
+	require.Equal(t, 30*time.Second, row.Timeout)
+
+	row.Discount = &testMoney{cents: 5}
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll([]testStruct{row})
+	require.NoError(t, err)
+	require.Equal(t, `Price,Discount,Prices,Timeout
+$12.50,$0.05,$1.00;$2.25,30s
+`, buf.String())
+
+	t.Run("Converter error", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Price,Discount,Prices,Timeout\nx,,,1"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, "invalid money", err.Error())
+	})
+	t.Run("Adapted", func(t *testing.T) {
+		am, err := m.Adapt(false, nil, WithConverter[time.Duration](func(val string, quoted bool, record []string) (time.Duration, error) {
+			mins, err := strconv.Atoi(val)
+			return time.Duration(mins) * time.Minute, err
+		}))
+		require.NoError(t, err)
+		row, err := am.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Minute, row.Timeout)
+		// original mapper converters unaffected...
+		row, err = m.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Second, row.Timeout)
+	})
+	t.Run("Without converter", func(t *testing.T) {
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+	})
+}
+
+type testStatus string
+
+type testPriority int
+
+type testLevel int
+
+func (l testLevel) String() string {
+	if l == 0 {
+		return "low"
+	}
+	return "high"
+}
+
+func TestMapper_Enums_Stringer(t *testing.T) {
+	type testStruct struct {
+		Level testLevel `csv:"Level"`
+	}
+	m, err := NewMapper[testStruct](WithEnum[testLevel](0, 1))
+	require.NoError(t, err)
+	row, err := m.Reader(strings.NewReader("Level\n1"), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, testLevel(1), row.Level)
+	_, err = m.Reader(strings.NewReader("Level\nhigh"), nil).Read()
+	require.Error(t, err)
+	require.Equal(t, `value "high" is not one of "0", "1"`, err.Error())
+}
+
+func TestMapper_Enums(t *testing.T) {
+	type testStruct struct {
+		Status   testStatus   `csv:"Status"`
+		Previous *testStatus  `csv:"Previous"`
+		Priority testPriority `csv:"Priority"`
+		Colour   string       `csv:"Colour,enum=red|green|blue,trim"`
+		Statuses []testStatus `csv:"Statuses,sep=;"`
+		Override testStatus   `csv:"Override,enum=on|off"`
+	}
+	m, err := NewMapper[testStruct](WithEnum[testStatus]("active", "inactive"), WithEnum[testPriority](1, 2, 3))
+	require.NoError(t, err)
+
+	const data = `Status,Previous,Priority,Colour,Statuses,Override
+active,,2, red ,active;inactive,off`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, testStatus("active"), row.Status)
+	require.Nil(t, row.Previous)
+	require.Equal(t, testPriority(2), row.Priority)
+	require.Equal(t, "red", row.Colour)
+	require.Equal(t, []testStatus{"active", "inactive"}, row.Statuses)
+	require.Equal(t, testStatus("off"), row.Override)
+
+	testCases := []struct {
+		data      string
+		expectErr string
+	}{
+		{
+			data:      "pending,,1,red,,on",
+			expectErr: `value "pending" is not one of "active", "inactive"`,
+		},
+		{
+			data:      "active,Active,1,red,,on",
+			expectErr: `value "Active" is not one of "active", "inactive"`,
+		},
+		{
+			data:      "active,,4,red,,on",
+			expectErr: `value "4" is not one of "1", "2", "3"`,
+		},
+		{
+			data:      "active,,1,yellow,,on",
+			expectErr: `value "yellow" is not one of "red", "green", "blue"`,
+		},
+		{
+			data:      "active,,1,red,active;x,on",
+			expectErr: `value "x" is not one of "active", "inactive"`,
+		},
+		{
+			data:      "active,,1,red,,active",
+			expectErr: `value "active" is not one of "on", "off"`,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			_, err := m.Reader(strings.NewReader("Status,Previous,Priority,Colour,Statuses,Override\n"+tc.data), nil).Read()
+			require.Error(t, err)
+			require.Equal(t, tc.expectErr, err.Error())
+		})
+	}
+}
@@ -1,2 +1,2 @@
-// Package csv - Go package to replace stdlib encoding/csv reader - with more exposed info
+// Package csv - Go package to replace stdlib encoding/csv reader (and writer) - with more exposed info and quoting control
 package csv
@@ -1,6 +1,6 @@
 package csv
 
-// Comma is an option that can be used for NewReader to set Reader.Comma
+// Comma is an option that can be used for NewReader / NewWriter to set Reader.Comma / Writer.Comma
 type Comma rune
 
 // Comment is an option that can be used for NewReader to set Reader.Comment
@@ -15,7 +15,7 @@ type LazyQuotes bool
 // TrimLeadingSpace is an option that can be used for NewReader to set Reader.TrimLeadingSpace
 type TrimLeadingSpace bool
 
-// NoHeader is an option that can be used for NewReader to set Reader.NoHeader
+// NoHeader is an option that can be used for NewReader / NewWriter to set Reader.NoHeader / Writer.NoHeader
 type NoHeader bool
 
 // ReuseRecord is an option that can be used for NewReader to set Reader.ReuseRecord
@@ -23,3 +23,9 @@ type ReuseRecord bool
 
 // NoSkipEmptyLines is an option that can be used for NewReader to set Reader.NoSkipEmptyLines
 type NoSkipEmptyLines bool
+
+// LineTerminator is an option that can be used for NewWriter to set Writer.LineTerminator
+type LineTerminator string
+
+// Header is an option that can be used for NewWriter to set Writer.Header
+type Header []string
@@ -245,6 +245,22 @@ func (r *Reader) FieldQuoted(field int) bool {
 	return r.fieldPositions[field].quoted
 }
 
+// RecordQuoting returns the quoting of each field in the slice most recently
+// returned by [Reader.Read] - QuoteAll for quoted fields and QuoteMinimal for unquoted fields
+//
+// This can be passed to [Writer.WriteQuoted] to write the record with the same quoting as it was read
+func (r *Reader) RecordQuoting() []QuotePolicy {
+	result := make([]QuotePolicy, len(r.fieldPositions))
+	for i, p := range r.fieldPositions {
+		if p.quoted {
+			result[i] = QuoteAll
+		} else {
+			result[i] = QuoteMinimal
+		}
+	}
+	return result
+}
+
 // InputOffset returns the input stream byte offset of the current reader
 // position. The offset gives the location of the end of the most recently
 // read row and the beginning of the next row.
@@ -508,6 +524,22 @@ func (r *Reader) Header() ([]string, bool) {
 	return r.header, !r.NoHeader
 }
 
+// ReadHeader reads the header, if it has not already been read, and returns it
+//
+// Calling ReadHeader is only necessary to obtain the header before any records are read - Read automatically reads the header.
+// If NoHeader is set, ReadHeader returns nil
+func (r *Reader) ReadHeader() ([]string, error) {
+	if !r.NoHeader && !r.headerRead && r.numLine == 0 {
+		record, err := r.readRecord(nil)
+		if err != nil {
+			return nil, err
+		}
+		r.headerRead = true
+		r.header = record
+	}
+	return r.header, nil
+}
+
 // CurrentLine returns the current line number being read
 func (r *Reader) CurrentLine() int {
 	if len(r.fieldPositions) > 0 {
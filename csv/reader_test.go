@@ -781,3 +781,42 @@ func TestReader_NoSkipEmptyLines(t *testing.T) {
 	require.NoError(t, err)
 	_ = rec
 }
+
+func TestReader_RecordQuoting(t *testing.T) {
+	const data = `"Foo",Bar,"Baz"`
+	r := NewReader(strings.NewReader(data))
+	r.NoHeader = true
+	require.Empty(t, r.RecordQuoting())
+	_, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, []QuotePolicy{QuoteAll, QuoteMinimal, QuoteAll}, r.RecordQuoting())
+}
+
+func TestReader_ReadHeader(t *testing.T) {
+	const data = `Foo,Bar,Baz
+Aaa,Bbb,Ccc`
+	r := NewReader(strings.NewReader(data))
+	hdr, err := r.ReadHeader()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Foo", "Bar", "Baz"}, hdr)
+	hdr, err = r.ReadHeader()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Foo", "Bar", "Baz"}, hdr)
+	record, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Aaa", "Bbb", "Ccc"}, record)
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+
+	r = NewReader(strings.NewReader(data), NoHeader(true))
+	hdr, err = r.ReadHeader()
+	require.NoError(t, err)
+	require.Nil(t, hdr)
+	record, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Foo", "Bar", "Baz"}, record)
+
+	r = NewReader(strings.NewReader(""))
+	_, err = r.ReadHeader()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,334 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrQuoteRequired is returned by Writer when a field requires quoting but the quote policy is QuoteNever
+var ErrQuoteRequired = errors.New("csv: field requires quoting")
+
+// QuotePolicy determines how fields are quoted by a Writer
+type QuotePolicy int
+
+const (
+	// QuoteDefault is the default quote policy
+	//
+	// For a Writer, it is the same as QuoteMinimal - for an individual field (see Writer.WriteQuoted), it means use the Writer.QuotePolicy
+	QuoteDefault QuotePolicy = iota
+	// QuoteMinimal only quotes fields that require quoting (i.e. fields that contain the delimiter, a quote or a line break - or start with a space)
+	QuoteMinimal
+	// QuoteAll quotes all fields
+	QuoteAll
+	// QuoteNonNumeric quotes all fields that are not plain decimal numbers (e.g. "-12.5") - empty fields are not quoted
+	//
+	// Exponents (e.g. "1e5"), hex floats, "NaN" and "Inf" are not plain decimal numbers (so are quoted)
+	QuoteNonNumeric
+	// QuoteNever never quotes fields - writing a field that requires quoting fails with ErrQuoteRequired
+	QuoteNever
+)
+
+// A Writer writes records using CSV encoding.
+//
+// As returned by [NewWriter], a Writer writes records terminated by a
+// newline and uses ',' as the field delimiter. The exported fields can be
+// changed to customize the details before
+// the first call to [Writer.Write] or [Writer.WriteAll].
+//
+// The writes of individual records are buffered.
+// After all data has been written, the client should call the
+// [Writer.Flush] method to guarantee all data has been forwarded to
+// the underlying [io.Writer].  Any errors that occurred should
+// be checked by calling the [Writer.Error] method.
+type Writer struct {
+	// Comma is the field delimiter.
+	// It is set to comma (',') by NewWriter.
+	Comma rune
+
+	// QuotePolicy determines how fields are quoted
+	QuotePolicy QuotePolicy
+
+	// LineTerminator is the line terminator written after each record (if empty, "\n" is used)
+	//
+	// If LineTerminator is "\r\n", line breaks within quoted fields are also written as "\r\n"
+	LineTerminator string
+
+	// Header, if set, is written as the first record (unless NoHeader is true)
+	Header []string
+
+	// NoHeader indicates that the header should not be written
+	NoHeader bool
+
+	// headerWritten flags when the header has already been written
+	headerWritten bool
+
+	w *bufio.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer, options ...any) *Writer {
+	result := &Writer{
+		Comma: ',',
+		w:     bufio.NewWriter(w),
+	}
+	for _, o := range options {
+		switch opt := o.(type) {
+		case Comma:
+			result.Comma = rune(opt)
+		case QuotePolicy:
+			result.QuotePolicy = opt
+		case LineTerminator:
+			result.LineTerminator = string(opt)
+		case Header:
+			result.Header = opt
+		case NoHeader:
+			result.NoHeader = bool(opt)
+		}
+	}
+	return result
+}
+
+// Write writes a single CSV record to w along with any necessary quoting.
+// A record is a slice of strings with each string being one field.
+// Writes are buffered, so [Writer.Flush] must eventually be called to ensure
+// that the record is written to the underlying [io.Writer].
+//
+// If the header has not yet been written, it is written before the record.
+func (w *Writer) Write(record []string) error {
+	return w.WriteQuoted(record, nil)
+}
+
+// WriteQuoted is the same as Write - except that the quote policy for each field can be specified
+//
+// Fields with a quote policy of QuoteDefault (or beyond the length of quoting) are quoted according to Writer.QuotePolicy.
+//
+// Reader.RecordQuoting can be used to write a record with the same quoting as it was read
+func (w *Writer) WriteQuoted(record []string, quoting []QuotePolicy) error {
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+	return w.writeRecord(record, quoting)
+}
+
+// WriteHeader writes the header (if set and not already written)
+//
+// Calling WriteHeader is only necessary when no records are written - Write and WriteQuoted automatically write the header
+func (w *Writer) WriteHeader() error {
+	if !w.headerWritten {
+		if !validDelim(w.Comma) {
+			return errInvalidDelim
+		}
+		w.headerWritten = true
+		if !w.NoHeader && w.Header != nil {
+			return w.writeRecord(w.Header, nil)
+		}
+	}
+	return nil
+}
+
+func (w *Writer) writeRecord(record []string, quoting []QuotePolicy) error {
+	if !validDelim(w.Comma) {
+		return errInvalidDelim
+	}
+
+	// Determine quoting of all fields before writing anything - so that a
+	// field that cannot be written does not result in a partial record.
+	quotes := make([]bool, len(record))
+	for n, field := range record {
+		policy := w.QuotePolicy
+		if n < len(quoting) && quoting[n] != QuoteDefault {
+			policy = quoting[n]
+		}
+		var err error
+		if quotes[n], err = w.fieldQuoted(field, policy); err != nil {
+			return err
+		}
+	}
+
+	for n, field := range record {
+		if n > 0 {
+			if _, err := w.w.WriteRune(w.Comma); err != nil {
+				return err
+			}
+		}
+
+		// If we don't have to have a quoted field then just
+		// write out the field and continue to the next field.
+		if !quotes[n] {
+			if _, err := w.w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := w.w.WriteByte('"'); err != nil {
+			return err
+		}
+		for len(field) > 0 {
+			// Search for special characters.
+			i := strings.IndexAny(field, "\"\r\n")
+			if i < 0 {
+				i = len(field)
+			}
+
+			// Copy verbatim everything before the special character.
+			if _, err := w.w.WriteString(field[:i]); err != nil {
+				return err
+			}
+			field = field[i:]
+
+			// Encode the special character.
+			if len(field) > 0 {
+				var err error
+				switch field[0] {
+				case '"':
+					_, err = w.w.WriteString(`""`)
+				case '\r':
+					if !w.useCRLF() {
+						err = w.w.WriteByte('\r')
+					}
+				case '\n':
+					if w.useCRLF() {
+						_, err = w.w.WriteString("\r\n")
+					} else {
+						err = w.w.WriteByte('\n')
+					}
+				}
+				field = field[1:]
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if err := w.w.WriteByte('"'); err != nil {
+			return err
+		}
+	}
+	_, err := w.w.WriteString(w.lineTerminator())
+	return err
+}
+
+func (w *Writer) lineTerminator() string {
+	if w.LineTerminator == "" {
+		return "\n"
+	}
+	return w.LineTerminator
+}
+
+func (w *Writer) useCRLF() bool {
+	return w.LineTerminator == "\r\n"
+}
+
+// Flush writes any buffered data to the underlying [io.Writer].
+// To check if an error occurred during Flush, call [Writer.Error].
+func (w *Writer) Flush() {
+	w.w.Flush()
+}
+
+// Error reports any error that has occurred during
+// a previous [Writer.Write] or [Writer.Flush].
+func (w *Writer) Error() error {
+	_, err := w.w.Write(nil)
+	return err
+}
+
+// WriteAll writes multiple CSV records to w using [Writer.Write] and
+// then calls [Writer.Flush], returning any error from the Flush.
+//
+// The header is written even if there are no records.
+func (w *Writer) WriteAll(records [][]string) error {
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+	for _, record := range records {
+		err := w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+// fieldQuoted reports whether the field is to be quoted according to the quote policy
+func (w *Writer) fieldQuoted(field string, policy QuotePolicy) (bool, error) {
+	switch policy {
+	case QuoteAll:
+		return true, nil
+	case QuoteNonNumeric:
+		if field == "" {
+			return false, nil
+		}
+		return !isDecimalNumber(field) || w.fieldNeedsQuotes(field), nil
+	case QuoteNever:
+		if w.fieldNeedsQuotes(field) {
+			return false, ErrQuoteRequired
+		}
+		return false, nil
+	}
+	return w.fieldNeedsQuotes(field), nil
+}
+
+// isDecimalNumber reports whether the field is a plain decimal number - an optional sign, digits and an optional decimal point
+func isDecimalNumber(field string) bool {
+	if field[0] == '-' || field[0] == '+' {
+		field = field[1:]
+	}
+	digits, points := 0, 0
+	for i := 0; i < len(field); i++ {
+		switch c := field[i]; {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.':
+			points++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && points <= 1
+}
+
+// fieldNeedsQuotes reports whether our field must be enclosed in quotes.
+// Fields with a Comma, fields with a quote or newline, and
+// fields which start with a space must be enclosed in quotes.
+// We used to quote empty strings, but we do not anymore (as of Go 1.4).
+// The two representations should be equivalent, but Postgres distinguishes
+// quoted vs non-quoted empty string during database imports, and it has
+// an option to force the quoted behavior for non-quoted CSV but it has
+// no option to force the non-quoted behavior for quoted CSV, making
+// CSV with quoted empty strings strictly less useful.
+// Not quoting the empty string also makes this package match the behavior
+// of Microsoft Excel and Google Drive.
+// For Postgres, quote the data terminating string `\.`.
+func (w *Writer) fieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+
+	if field == `\.` {
+		return true
+	}
+
+	if w.Comma < utf8.RuneSelf {
+		for i := 0; i < len(field); i++ {
+			c := field[i]
+			if c == '\n' || c == '\r' || c == '"' || c == byte(w.Comma) {
+				return true
+			}
+		}
+	} else {
+		if strings.ContainsRune(field, w.Comma) || strings.ContainsAny(field, "\"\r\n") {
+			return true
+		}
+	}
+
+	r1, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r1)
+}
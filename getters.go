@@ -0,0 +1,298 @@
+package csvamp
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/go-andiamo/csvamp/csv"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueGetter gets a CSV field value from a value (i.e. a struct field or slice element)
+type valueGetter func(v reflect.Value, record []string) (string, csv.QuotePolicy, error)
+
+// quoteEmptyValue is the quote policy for an empty (but not nil) pointer value - which is quoted (to distinguish it from nil)
+// unless the csv.Writer quote policy is csv.QuoteNever (in which case it is written as an empty field)
+const quoteEmptyValue = csv.QuotePolicy(-1)
+
+func buildGetter[T any](currentPath []int, fld reflect.StructField, opts *fieldOptions) (func(t *T, record []string) (string, csv.QuotePolicy, error), error) {
+	vg, err := buildValueGetter(fld.Type, opts)
+	if err != nil {
+		return nil, err
+	}
+	return func(t *T, record []string) (string, csv.QuotePolicy, error) {
+		if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, false); ok {
+			return vg(v, record)
+		}
+		// nil pointer to nested struct...
+		return "", csv.QuoteDefault, nil
+	}, nil
+}
+
+func buildValueGetter(typ reflect.Type, opts *fieldOptions) (valueGetter, error) {
+	if vg, ok := opts.formatter(typ); ok {
+		return vg, nil
+	}
+	fk := typ.Kind()
+	if fk == reflect.Ptr {
+		return buildPtrValueGetter(typ, opts)
+	}
+	switch typ {
+	case timeType:
+		layout, loc := opts.timeLayout(), opts.formatLocation()
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return formatTime(v, layout, loc), csv.QuoteDefault, nil
+		}, nil
+	case durationType:
+		return getterDuration, nil
+	case bigIntType:
+		return getterBigInt(opts.intBase(), opts.numberFormat()), nil
+	case bigFloatType:
+		return getterBigFloat(opts.numberFormat()), nil
+	case bigRatType:
+		return getterBigRat, nil
+	}
+	if isMarshalerCsvType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			// call MarshalCSV on pointer to the value...
+			m := addressOf(v).Interface().(CsvMarshaler)
+			val, err := m.MarshalCSV(record)
+			return val, csv.QuoteDefault, err
+		}, nil
+	} else if isMarshalerQuotedCsvType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			// call MarshalQuotedCSV on pointer to the value...
+			m := addressOf(v).Interface().(CsvQuotedMarshaler)
+			return marshalQuoted(m, record)
+		}, nil
+	} else if isMarshalerTextType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			// call MarshalText on pointer to the value...
+			m := addressOf(v).Interface().(encoding.TextMarshaler)
+			val, err := m.MarshalText()
+			return string(val), csv.QuoteDefault, err
+		}, nil
+	}
+	switch fk {
+	case reflect.Bool:
+		if bt := opts.boolTokens(); bt != nil {
+			return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+				return bt.format(v.Bool()), csv.QuoteDefault, nil
+			}, nil
+		}
+		return getterBool, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return getterInt(opts.intBase(), opts.numberFormat()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return getterUint(opts.intBase(), opts.numberFormat()), nil
+	case reflect.Float32:
+		return getterFloat(32, opts.numberFormat()), nil
+	case reflect.Float64:
+		return getterFloat(64, opts.numberFormat()), nil
+	case reflect.Complex64:
+		return getterComplex(64), nil
+	case reflect.Complex128:
+		return getterComplex(128), nil
+	case reflect.String:
+		return getterString, nil
+	case reflect.Slice:
+		return buildSliceValueGetter(typ, opts)
+	}
+	return nil, fmt.Errorf("struct field unsupported type: %s", fk.String())
+}
+
+// addressOf returns a pointer to the value (or to a copy of the value, if the value is not addressable)
+func addressOf(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr
+}
+
+func getterBool(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+	return strconv.FormatBool(v.Bool()), csv.QuoteDefault, nil
+}
+
+func getterInt(intBase *int, nf *NumberFormat) valueGetter {
+	base := baseOf(intBase)
+	if base == 0 {
+		base = 10
+	}
+	if nf != nil {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return nf.formatInteger(strconv.FormatInt(v.Int(), base)), csv.QuoteDefault, nil
+		}
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return strconv.FormatInt(v.Int(), base), csv.QuoteDefault, nil
+	}
+}
+
+func getterUint(intBase *int, nf *NumberFormat) valueGetter {
+	base := baseOf(intBase)
+	if base == 0 {
+		base = 10
+	}
+	if nf != nil {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return nf.formatInteger(strconv.FormatUint(v.Uint(), base)), csv.QuoteDefault, nil
+		}
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return strconv.FormatUint(v.Uint(), base), csv.QuoteDefault, nil
+	}
+}
+
+func getterFloat(bitSize int, nf *NumberFormat) valueGetter {
+	if nf != nil {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			return nf.formatFloat(strconv.FormatFloat(v.Float(), 'f', -1, bitSize)), csv.QuoteDefault, nil
+		}
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		return strconv.FormatFloat(v.Float(), 'f', -1, bitSize), csv.QuoteDefault, nil
+	}
+}
+
+func getterString(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+	return v.String(), csv.QuoteDefault, nil
+}
+
+func getterDuration(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+	return time.Duration(v.Int()).String(), csv.QuoteDefault, nil
+}
+
+func getterBytes(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+	return string(v.Bytes()), csv.QuoteDefault, nil
+}
+
+// buildSliceValueGetter builds a value getter for slices - where each element is joined (using the field option separator)
+func buildSliceValueGetter(typ reflect.Type, opts *fieldOptions) (valueGetter, error) {
+	et := typ.Elem()
+	switch et.Kind() {
+	case reflect.Uint8:
+		return getterBytes, nil
+	case reflect.Slice, reflect.Map:
+		return nil, fmt.Errorf("struct field unsupported type: []%s", et.Kind().String())
+	}
+	eg, err := buildValueGetter(et, opts)
+	if err != nil {
+		return nil, err
+	}
+	sep := opts.sliceSeparator()
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			var err error
+			if parts[i], _, err = eg(v.Index(i), record); err != nil {
+				return "", csv.QuoteDefault, err
+			}
+		}
+		return strings.Join(parts, sep), csv.QuoteDefault, nil
+	}, nil
+}
+
+func buildPtrValueGetter(typ reflect.Type, opts *fieldOptions) (valueGetter, error) {
+	et := typ.Elem()
+	if et.Kind() == reflect.Ptr {
+		return nil, fmt.Errorf("struct field unsupported type: *%s", et.Kind().String())
+	}
+	if _, ok := opts.formatter(et); ok {
+		return buildPtrElemValueGetter(et, opts)
+	} else if et == timeType || et == durationType || isBigType(et) {
+		// time and math/big types are text marshalers - but are formatted natively...
+		return buildPtrElemValueGetter(et, opts)
+	} else if isMarshalerCsvType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			if v.IsNil() {
+				return "", csv.QuoteDefault, nil
+			}
+			val, err := v.Interface().(CsvMarshaler).MarshalCSV(record)
+			return val, csv.QuoteDefault, err
+		}, nil
+	} else if isMarshalerQuotedCsvType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			if v.IsNil() {
+				return "", csv.QuoteDefault, nil
+			}
+			return marshalQuoted(v.Interface().(CsvQuotedMarshaler), record)
+		}, nil
+	} else if isMarshalerTextType(typ) {
+		return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+			if v.IsNil() {
+				return "", csv.QuoteDefault, nil
+			}
+			val, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(val), csv.QuoteDefault, err
+		}, nil
+	}
+	return buildPtrElemValueGetter(et, opts)
+}
+
+func buildPtrElemValueGetter(et reflect.Type, opts *fieldOptions) (valueGetter, error) {
+	eg, err := buildValueGetter(et, opts)
+	if err != nil {
+		return nil, fmt.Errorf("struct field unsupported type: *%s", et.Kind().String())
+	}
+	return func(v reflect.Value, record []string) (string, csv.QuotePolicy, error) {
+		if v.IsNil() {
+			return "", csv.QuoteDefault, nil
+		}
+		val, quoting, err := eg(v.Elem(), record)
+		if err == nil && val == "" {
+			// an empty (but not nil) pointer value is quoted - to distinguish it from nil...
+			return val, quoteEmptyValue, nil
+		}
+		return val, quoting, err
+	}, nil
+}
+
+func formatTime(v reflect.Value, layout string, loc *time.Location) string {
+	dt := v.Interface().(time.Time)
+	if loc != nil {
+		dt = dt.In(loc)
+	}
+	return dt.Format(layout)
+}
+
+func marshalQuoted(m CsvQuotedMarshaler, record []string) (string, csv.QuotePolicy, error) {
+	val, quoted, err := m.MarshalQuotedCSV(record)
+	if quoted {
+		return val, csv.QuoteAll, err
+	}
+	return val, csv.QuoteMinimal, err
+}
+
+var marshalerCsvType = reflect.TypeOf((*CsvMarshaler)(nil)).Elem()
+
+var marshalerQuotedCsvType = reflect.TypeOf((*CsvQuotedMarshaler)(nil)).Elem()
+
+var marshalerTextType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func isMarshalerCsvType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return reflect.PointerTo(t).Implements(marshalerCsvType)
+	} else {
+		return t.Implements(marshalerCsvType)
+	}
+}
+
+func isMarshalerQuotedCsvType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return reflect.PointerTo(t).Implements(marshalerQuotedCsvType)
+	} else {
+		return t.Implements(marshalerQuotedCsvType)
+	}
+}
+
+func isMarshalerTextType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return reflect.PointerTo(t).Implements(marshalerTextType)
+	} else {
+		return t.Implements(marshalerTextType)
+	}
+}
@@ -0,0 +1,314 @@
+package csvamp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-andiamo/csvamp/csv"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildGetter(t *testing.T) {
+	testCases := []struct {
+		sample    any
+		expectErr bool
+	}{
+		{
+			sample: struct{ Foo bool }{},
+		},
+		{
+			sample: struct{ Foo *bool }{},
+		},
+		{
+			sample: struct{ Foo int16 }{},
+		},
+		{
+			sample: struct{ Foo *int16 }{},
+		},
+		{
+			sample: struct{ Foo uint32 }{},
+		},
+		{
+			sample: struct{ Foo *uint32 }{},
+		},
+		{
+			sample: struct{ Foo float32 }{},
+		},
+		{
+			sample: struct{ Foo *float32 }{},
+		},
+		{
+			sample: struct{ Foo string }{},
+		},
+		{
+			sample: struct{ Foo *string }{},
+		},
+		{
+			sample: struct{ Foo []string }{},
+		},
+		{
+			sample: struct{ Foo []int }{},
+		},
+		{
+			sample: struct{ Foo *[]string }{},
+		},
+		{
+			sample:    struct{ Foo [][]int }{},
+			expectErr: true,
+		},
+		{
+			sample:    struct{ Foo map[string]string }{},
+			expectErr: true,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			vo := reflect.TypeOf(tc.sample)
+			fld := vo.Field(0)
+			fn, err := buildGetter[any]([]int{0}, fld, nil)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "struct field unsupported type:")
+				require.Nil(t, fn)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, fn)
+			}
+		})
+	}
+}
+
+func TestGetters(t *testing.T) {
+	type testStruct struct {
+		Bool        bool
+		PtrBool     *bool
+		Int         int
+		PtrInt      *int
+		Int8        int8
+		Uint        uint
+		PtrUint     *uint
+		Uint64      uint64
+		Float32     float32
+		Float64     float64
+		PtrFloat    *float64
+		String      string
+		PtrString   *string
+		SliceString []string
+	}
+	b := true
+	i := -1
+	u := uint(1)
+	f := 1.5
+	str := "foo"
+	testCases := []struct {
+		sample testStruct
+		expect []string
+	}{
+		{
+			sample: testStruct{},
+			expect: []string{"false", "", "0", "", "0", "0", "", "0", "0", "0", "", "", "", ""},
+		},
+		{
+			sample: testStruct{
+				Bool:        true,
+				PtrBool:     &b,
+				Int:         -2,
+				PtrInt:      &i,
+				Int8:        -8,
+				Uint:        2,
+				PtrUint:     &u,
+				Uint64:      64,
+				Float32:     1.25,
+				Float64:     2.5,
+				PtrFloat:    &f,
+				String:      "bar",
+				PtrString:   &str,
+				SliceString: []string{"a", "b"},
+			},
+			expect: []string{"true", "true", "-2", "-1", "-8", "2", "1", "64", "1.25", "2.5", "1.5", "bar", "foo", "a,b"},
+		},
+	}
+	rt := reflect.TypeOf(testStruct{})
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			for f := 0; f < rt.NumField(); f++ {
+				fn, err := buildGetter[testStruct]([]int{f}, rt.Field(f), nil)
+				require.NoError(t, err)
+				s, _, err := fn(&tc.sample, nil)
+				require.NoError(t, err)
+				require.Equal(t, tc.expect[f], s)
+			}
+		})
+	}
+}
+
+func TestGetters_Marshalers(t *testing.T) {
+	type testStruct struct {
+		Csv          MyMarshalString
+		PtrCsv       *MyMarshalString
+		Quoted       MyQuotedMarshalString
+		PtrQuoted    *MyQuotedMarshalString
+		Text         MyMarshalText
+		PtrText      *MyMarshalText
+		ValueRcvr    MyValueMarshalString
+		PtrValueRcvr *MyValueMarshalString
+	}
+	csvStr := MyMarshalString("csv")
+	quotedStr := MyQuotedMarshalString("quoted")
+	textStr := MyMarshalText("text")
+	valueStr := MyValueMarshalString("value")
+	testCases := []struct {
+		sample       testStruct
+		expect       []string
+		expectQuoted []csv.QuotePolicy
+	}{
+		{
+			sample:       testStruct{},
+			expect:       []string{"<>", "", "", "", "[]", "", "()", ""},
+			expectQuoted: []csv.QuotePolicy{csv.QuoteDefault, csv.QuoteDefault, csv.QuoteMinimal, csv.QuoteDefault, csv.QuoteDefault, csv.QuoteDefault, csv.QuoteDefault, csv.QuoteDefault},
+		},
+		{
+			sample: testStruct{
+				Csv:          "a",
+				PtrCsv:       &csvStr,
+				Quoted:       "b",
+				PtrQuoted:    &quotedStr,
+				Text:         "c",
+				PtrText:      &textStr,
+				ValueRcvr:    "d",
+				PtrValueRcvr: &valueStr,
+			},
+			expect:       []string{"<a>", "<csv>", "b", "quoted", "[c]", "[text]", "(d)", "(value)"},
+			expectQuoted: []csv.QuotePolicy{csv.QuoteDefault, csv.QuoteDefault, csv.QuoteAll, csv.QuoteAll, csv.QuoteDefault, csv.QuoteDefault, csv.QuoteDefault, csv.QuoteDefault},
+		},
+	}
+	rt := reflect.TypeOf(testStruct{})
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			for f := 0; f < rt.NumField(); f++ {
+				fn, err := buildGetter[testStruct]([]int{f}, rt.Field(f), nil)
+				require.NoError(t, err)
+				s, quoting, err := fn(&tc.sample, []string{"x"})
+				require.NoError(t, err)
+				require.Equal(t, tc.expect[f], s)
+				require.Equal(t, tc.expectQuoted[f], quoting)
+			}
+		})
+	}
+}
+
+func TestGetters_MarshalerErrors(t *testing.T) {
+	type testStruct struct {
+		Csv       MyBadMarshalString
+		PtrCsv    *MyBadMarshalString
+		Quoted    MyBadQuotedMarshalString
+		PtrQuoted *MyBadQuotedMarshalString
+		Text      MyBadMarshalText
+		PtrText   *MyBadMarshalText
+	}
+	sample := testStruct{
+		PtrCsv:    new(MyBadMarshalString),
+		PtrQuoted: new(MyBadQuotedMarshalString),
+		PtrText:   new(MyBadMarshalText),
+	}
+	rt := reflect.TypeOf(sample)
+	for f := 0; f < rt.NumField(); f++ {
+		fn, err := buildGetter[testStruct]([]int{f}, rt.Field(f), nil)
+		require.NoError(t, err)
+		_, _, err = fn(&sample, nil)
+		require.Error(t, err)
+		require.Equal(t, "fooey", err.Error())
+	}
+}
+
+type MyMarshalString string
+
+func (my *MyMarshalString) MarshalCSV(record []string) (string, error) {
+	return "<" + string(*my) + ">", nil
+}
+
+type MyBadMarshalString string
+
+func (my *MyBadMarshalString) MarshalCSV(record []string) (string, error) {
+	return "", errors.New("fooey")
+}
+
+type MyQuotedMarshalString string
+
+func (my *MyQuotedMarshalString) MarshalQuotedCSV(record []string) (string, bool, error) {
+	return string(*my), *my != "", nil
+}
+
+type MyBadQuotedMarshalString string
+
+func (my *MyBadQuotedMarshalString) MarshalQuotedCSV(record []string) (string, bool, error) {
+	return "", false, errors.New("fooey")
+}
+
+type MyMarshalText string
+
+func (my *MyMarshalText) MarshalText() ([]byte, error) {
+	return []byte("[" + string(*my) + "]"), nil
+}
+
+type MyBadMarshalText string
+
+func (my *MyBadMarshalText) MarshalText() ([]byte, error) {
+	return nil, errors.New("fooey")
+}
+
+type MyValueMarshalString string
+
+func (my MyValueMarshalString) MarshalCSV(record []string) (string, error) {
+	return "(" + string(my) + ")", nil
+}
+
+func TestGetters_Time(t *testing.T) {
+	type testStruct struct {
+		Time        time.Time
+		PtrTime     *time.Time
+		Duration    time.Duration
+		PtrDuration *time.Duration
+	}
+	dt := time.Date(2025, 1, 2, 23, 4, 5, 0, time.UTC)
+	d := 90 * time.Second
+	sample := testStruct{
+		Time:        dt,
+		PtrTime:     &dt,
+		Duration:    d,
+		PtrDuration: &d,
+	}
+	rt := reflect.TypeOf(sample)
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	testCases := []struct {
+		opts   *fieldOptions
+		expect []string
+	}{
+		{
+			opts:   nil,
+			expect: []string{"2025-01-02T23:04:05Z", "2025-01-02T23:04:05Z", "1m30s", "1m30s"},
+		},
+		{
+			opts:   &fieldOptions{layout: "02/01/2006"},
+			expect: []string{"02/01/2025", "02/01/2025", "1m30s", "1m30s"},
+		},
+		{
+			opts:   &fieldOptions{layout: "2006-01-02 15:04", location: loc},
+			expect: []string{"2025-01-03 08:04", "2025-01-03 08:04", "1m30s", "1m30s"},
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			for f := 0; f < rt.NumField(); f++ {
+				fn, err := buildGetter[testStruct]([]int{f}, rt.Field(f), tc.opts)
+				require.NoError(t, err)
+				s, _, err := fn(&sample, nil)
+				require.NoError(t, err)
+				require.Equal(t, tc.expect[f], s)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package csvamp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const byteOrderMark = "\uFEFF"
+
+// headerAliasSeparator separates alternative header names referenced by a struct field (e.g. `csv:"Postcode|Zip|Postal Code"`)
+//
+// a literal "|" within a header name is escaped by doubling it (e.g. `csv:"In||Out"` references the header "In|Out")
+const headerAliasSeparator = "|"
+
+// headerRegexPrefix denotes a header regex referenced by a struct field (e.g. `csv:"~^Q\d \d{4} Revenue$"`)
+const headerRegexPrefix = "~"
+
+// headerAliases returns the alternative header names for a header name referenced by a struct field (with any escaped "||" unescaped)
+//
+// a header regex has no aliases (as regex alternation can be used)
+func headerAliases(name string) []string {
+	if isHeaderRegex(name) {
+		return []string{name}
+	}
+	result := make([]string, 0, 1)
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		if !strings.HasPrefix(name[i:], headerAliasSeparator) {
+			sb.WriteByte(name[i])
+		} else if strings.HasPrefix(name[i+len(headerAliasSeparator):], headerAliasSeparator) {
+			sb.WriteString(headerAliasSeparator)
+			i += 2*len(headerAliasSeparator) - 1
+		} else {
+			result = append(result, sb.String())
+			sb.Reset()
+			i += len(headerAliasSeparator) - 1
+		}
+	}
+	return append(result, sb.String())
+}
+
+// joinHeaderAliases joins alternative header names (escaping any literal "|") - the reverse of headerAliases
+func joinHeaderAliases(aliases []string) string {
+	escaped := make([]string, len(aliases))
+	for i, alias := range aliases {
+		if isHeaderRegex(alias) {
+			escaped[i] = alias
+		} else {
+			escaped[i] = strings.ReplaceAll(alias, headerAliasSeparator, headerAliasSeparator+headerAliasSeparator)
+		}
+	}
+	return strings.Join(escaped, headerAliasSeparator)
+}
+
+// prefixHeader prefixes a header name referenced by a struct field within a nested struct tagged with a header prefix (e.g. `csv:"billing_,prefix"`)
+//
+// each alias is prefixed - and header regexes are anchored to match the prefix
+func prefixHeader(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	aliases := headerAliases(name)
+	for i, alias := range aliases {
+		if isHeaderRegex(alias) {
+			rx := alias[len(headerRegexPrefix):]
+			if strings.HasPrefix(rx, "^") {
+				rx = rx[1:]
+			} else {
+				rx = ".*(?:" + rx + ")"
+			}
+			aliases[i] = headerRegexPrefix + "^" + regexp.QuoteMeta(prefix) + "(?:" + rx + ")"
+		} else {
+			aliases[i] = prefix + alias
+		}
+	}
+	return joinHeaderAliases(aliases)
+}
+
+// headerOccurrenceSeparator separates a header name and the specific occurrence of that header (e.g. `csv:"Amount#2"`)
+const headerOccurrenceSeparator = "#"
+
+// headerOccurrence splits a header name referencing a specific (1 based) occurrence of a duplicated header (e.g. "Amount#2")
+func headerOccurrence(name string) (string, int, bool) {
+	if i := strings.LastIndex(name, headerOccurrenceSeparator); i != -1 {
+		if n, err := strconv.Atoi(name[i+len(headerOccurrenceSeparator):]); err == nil && n > 0 {
+			return name[:i], n, true
+		}
+	}
+	return name, 0, false
+}
+
+func isHeaderRegex(name string) bool {
+	return strings.HasPrefix(name, headerRegexPrefix)
+}
+
+// headerWildcard denotes a header pattern referenced by a struct field (e.g. `csv:"Phone*"`) - matching any run of characters
+const headerWildcard = "*"
+
+func isHeaderPattern(name string) bool {
+	return !isHeaderRegex(name) && strings.Contains(name, headerWildcard)
+}
+
+// matchesHeaderPattern determines whether the normalized CSV header matches the header pattern (e.g. "Phone*")
+//
+// the literal parts of the pattern are normalized individually
+func (m *mapper[T]) matchesHeaderPattern(pattern string, h string) bool {
+	parts := strings.Split(pattern, headerWildcard)
+	for i, part := range parts {
+		part = m.normalizeHeader(part)
+		switch {
+		case i == 0:
+			if !strings.HasPrefix(h, part) {
+				return false
+			}
+			h = h[len(part):]
+		case i == len(parts)-1:
+			return strings.HasSuffix(h, part)
+		default:
+			idx := strings.Index(h, part)
+			if idx == -1 {
+				return false
+			}
+			h = h[idx+len(part):]
+		}
+	}
+	return true
+}
+
+func (m *mapper[T]) compileHeaderRegex(name string) error {
+	if isHeaderRegex(name) {
+		if _, ok := m.headerRegexes[name]; !ok {
+			rx, err := regexp.Compile(name[len(headerRegexPrefix):])
+			if err != nil {
+				return fmt.Errorf("invalid csv header regex %q", name)
+			}
+			m.headerRegexes[name] = rx
+		}
+	}
+	return nil
+}
+
+// normalizeHeader normalizes a CSV header name (or a header name referenced by a struct field) according to the mapper options
+func (m *mapper[T]) normalizeHeader(h string) string {
+	if m.headerNormalization&HeaderStripBOM != 0 {
+		h = strings.TrimPrefix(h, byteOrderMark)
+	}
+	if m.headerNormalization&HeaderCollapsePunctuation != 0 {
+		h = strings.Join(strings.FieldsFunc(h, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ")
+	} else if m.headerNormalization&HeaderTrimSpace != 0 {
+		h = strings.Join(strings.Fields(h), " ")
+	}
+	if m.headerNormalization&HeaderFoldCase != 0 {
+		h = strings.ToLower(h)
+	}
+	if m.headerNormalizer != nil {
+		h = m.headerNormalizer(h)
+	}
+	return h
+}
+
+// resolveHeaders resolves the CSV header indices for each header name referenced by struct fields
+//
+// header regexes are matched against the normalized CSV headers
+//
+// the returned error, if any, is a *HeadersError
+func (rc *readerContext[T]) resolveHeaders(headers []string) error {
+	normalized := make([]string, len(headers))
+	occurrences := make(map[string][]int, len(headers))
+	for i, h := range headers {
+		normalized[i] = rc.mapper.normalizeHeader(h)
+		occurrences[normalized[i]] = append(occurrences[normalized[i]], i)
+	}
+	rc.headers = headers
+	rc.csvHeaders = make(map[string]int, len(rc.mapper.csvFieldNames))
+	rc.csvColumns = make(map[string][]int, len(rc.mapper.csvFieldColumns))
+	rc.consumed = make(map[int]bool, len(rc.mapper.csvFieldNames))
+	hdrsErr := &HeadersError{}
+	for _, mapping := range rc.mapper.Mappings() {
+		if name := mapping.CsvFieldName; name != "" && !isIndexRange(name) {
+			// the first alias present wins...
+			var matched []int
+			for _, alias := range headerAliases(name) {
+				if matched = rc.matchHeaders(alias, normalized, occurrences); len(matched) > 0 {
+					break
+				}
+			}
+			_, optional := rc.mapper.fieldTags[mapping.FieldName].options[csvTagOptionOptional]
+			if len(matched) == 0 && !optional && !rc.mapper.ignoreUnknownFieldNames {
+				hdrsErr.Missing = append(hdrsErr.Missing, name)
+			}
+			if _, ok := rc.mapper.csvFieldColumns[name]; ok {
+				if len(matched) > 0 || optional {
+					rc.csvColumns[name] = matched
+				}
+				for _, idx := range matched {
+					rc.consumed[idx] = true
+				}
+			} else if len(matched) > 0 {
+				rc.csvHeaders[name] = matched[0]
+				rc.consumed[matched[0]] = true
+			} else if optional {
+				// negative index denotes optional header not present...
+				rc.csvHeaders[name] = -1
+			}
+		}
+	}
+	if rc.mapper.disallowDuplicateHeaders {
+		for i, h := range normalized {
+			if occ := occurrences[h]; len(occ) > 1 && occ[0] == i {
+				hdrsErr.Duplicate = append(hdrsErr.Duplicate, headers[i])
+			}
+		}
+	}
+	if rc.mapper.disallowUnknownColumns {
+		hdrsErr.Unexpected = rc.unknownHeaders()
+	}
+	if len(hdrsErr.Missing) > 0 || len(hdrsErr.Duplicate) > 0 || len(hdrsErr.Unexpected) > 0 {
+		return hdrsErr
+	}
+	return nil
+}
+
+// requiresHeaders determines whether the CSV headers are required (and therefore must be read and resolved)
+func (rc *readerContext[T]) requiresHeaders() bool {
+	if len(rc.mapper.csvFieldNames) > 0 || len(rc.mapper.csvFieldColumns) > 0 || rc.mapper.extraByHeader ||
+		rc.mapper.disallowUnknownColumns || rc.mapper.disallowDuplicateHeaders {
+		return true
+	}
+	for _, fr := range rc.mapper.csvFieldRanges {
+		if fr.byHeader {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownHeaders returns the CSV headers not mapped to any struct field (no headers are unknown if there is a struct field tagged with "[extra]")
+func (rc *readerContext[T]) unknownHeaders() []string {
+	result := make([]string, 0)
+	if rc.mapper.extraMapper == nil {
+		for i, h := range rc.headers {
+			if !rc.mapper.isIndexMapped(i+1) && !rc.consumed[i] {
+				result = append(result, h)
+			}
+		}
+	}
+	return result
+}
+
+// HeadersError is the error returned by ReaderContext.ValidateHeaders (and when reading) when the CSV headers do not match the struct field mappings
+type HeadersError struct {
+	// Missing are the header names referenced by struct fields that are not present in the CSV
+	Missing []string
+	// Duplicate are the CSV headers that occur more than once in the CSV (only when the DisallowDuplicateHeaders option is used)
+	Duplicate []string
+	// Unexpected are the CSV headers not mapped to any struct field (only when the DisallowUnknownColumns option is used)
+	Unexpected []string
+}
+
+func (e *HeadersError) Error() string {
+	parts := make([]string, 0, 3)
+	if len(e.Missing) > 0 {
+		parts = append(parts, headersPhrase(e.Missing, "not present"))
+	}
+	if len(e.Duplicate) > 0 {
+		parts = append(parts, headersPhrase(e.Duplicate, "duplicated"))
+	}
+	if len(e.Unexpected) > 0 {
+		parts = append(parts, headersPhrase(e.Unexpected, "unknown"))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func headersPhrase(headers []string, what string) string {
+	quoted := make([]string, len(headers))
+	for i, s := range headers {
+		quoted[i] = strconv.Quote(s)
+	}
+	if len(headers) == 1 {
+		return fmt.Sprintf("csv header %s %s", quoted[0], what)
+	}
+	return fmt.Sprintf("csv headers %s %s", strings.Join(quoted, ", "), what)
+}
+
+// matchHeaders returns the indices of the CSV headers that match the header name (or header regex)
+//
+// a header name that occurs more than once in the CSV matches the last occurrence (unless a specific occurrence is referenced - e.g. "Amount#2")
+func (rc *readerContext[T]) matchHeaders(name string, normalized []string, occurrences map[string][]int) (matched []int) {
+	if rx, ok := rc.mapper.headerRegexes[name]; ok {
+		matched = make([]int, 0)
+		for i, h := range normalized {
+			if rx.MatchString(h) {
+				matched = append(matched, i)
+			}
+		}
+		return matched
+	} else if isHeaderPattern(name) {
+		matched = make([]int, 0)
+		for i, h := range normalized {
+			if rc.mapper.matchesHeaderPattern(name, h) {
+				matched = append(matched, i)
+			}
+		}
+		return matched
+	} else if occ, ok := occurrences[rc.mapper.normalizeHeader(name)]; ok {
+		return occ[len(occ)-1:]
+	} else if hdr, n, ok := headerOccurrence(name); ok {
+		if occ := occurrences[rc.mapper.normalizeHeader(hdr)]; n <= len(occ) {
+			return occ[n-1 : n]
+		}
+	}
+	return nil
+}
+
+// extraColumns returns the CSV fields not consumed by any struct field (as used for a struct field tagged with "[extra]")
+func (rc *readerContext[T]) extraColumns(record []string) []csvColumn {
+	result := make([]csvColumn, 0)
+	for i, v := range record {
+		if !rc.mapper.isIndexMapped(i+1) && !rc.consumed[i] {
+			col := csvColumn{index: i, value: v, quoted: rc.reader.FieldQuoted(i)}
+			if i < len(rc.headers) {
+				col.header = rc.headers[i]
+			}
+			result = append(result, col)
+		}
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package csvamp
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestMapper_NormalizeHeader(t *testing.T) {
+	testCases := []struct {
+		header        string
+		normalization HeaderNormalization
+		normalizer    HeaderNormalizer
+		expect        string
+	}{
+		{
+			header: " First  Name ",
+			expect: " First  Name ",
+		},
+		{
+			header:        " First  Name ",
+			normalization: HeaderTrimSpace,
+			expect:        "First Name",
+		},
+		{
+			header:        " First  Name ",
+			normalization: HeaderFoldCase,
+			expect:        " first  name ",
+		},
+		{
+			header:        "\uFEFFFirst Name",
+			normalization: HeaderStripBOM,
+			expect:        "First Name",
+		},
+		{
+			header:        "First Name\uFEFF",
+			normalization: HeaderStripBOM,
+			expect:        "First Name\uFEFF",
+		},
+		{
+			header:        "_First__Name. ",
+			normalization: HeaderCollapsePunctuation,
+			expect:        "First Name",
+		},
+		{
+			header:        "\uFEFF First-Name ",
+			normalization: HeaderNormalizeAll,
+			expect:        "first name",
+		},
+		{
+			header:     "First Name",
+			normalizer: strings.ToUpper,
+			expect:     "FIRST NAME",
+		},
+		{
+			header:        "First_Name",
+			normalization: HeaderFoldCase,
+			normalizer: func(s string) string {
+				return strings.ReplaceAll(s, "_", "")
+			},
+			expect: "firstname",
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]%q", i+1, tc.header), func(t *testing.T) {
+			m := &mapper[struct{}]{
+				headerNormalization: tc.normalization,
+				headerNormalizer:    tc.normalizer,
+			}
+			require.Equal(t, tc.expect, m.normalizeHeader(tc.header))
+		})
+	}
+}
+
+func TestHeaderAliases(t *testing.T) {
+	testCases := []struct {
+		name   string
+		expect []string
+	}{
+		{name: "Postcode", expect: []string{"Postcode"}},
+		{name: "Postcode|Zip|Postal Code", expect: []string{"Postcode", "Zip", "Postal Code"}},
+		{name: "In||Out|I/O", expect: []string{"In|Out", "I/O"}},
+		{name: "||", expect: []string{"|"}},
+		{name: "a|||b", expect: []string{"a|", "b"}},
+		{name: "~^a|b$", expect: []string{"~^a|b$"}},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]%q", i+1, tc.name), func(t *testing.T) {
+			aliases := headerAliases(tc.name)
+			require.Equal(t, tc.expect, aliases)
+			require.Equal(t, aliases, headerAliases(joinHeaderAliases(aliases)))
+		})
+	}
+	t.Run("Prefixed", func(t *testing.T) {
+		require.Equal(t, "x_In||Out|x_I/O", prefixHeader("x_", "In||Out|I/O"))
+	})
+}
@@ -1,13 +1,16 @@
 package csvamp
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-andiamo/csvamp/csv"
 	"io"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,6 +18,7 @@ const (
 	csvTagLine    = "[line]"
 	csvTagRaw     = "[raw]"
 	csvTagRawData = "[rawData]"
+	csvTagExtra   = "[extra]"
 )
 
 // Mapper is an interface for mapping structs onto CSV
@@ -31,6 +35,21 @@ type Mapper[T any] interface {
 	//
 	// the postProcessor func, if provided, can be used to validate (or modify) the struct after it has been read
 	ReaderContext(r *csv.Reader, postProcessor func(row *T) error) ReaderContext[T]
+	// Writer returns a writer context for the mapper using the provided io.Writer
+	//
+	// the header line written is built from the mappings - indexed fields use the struct field name (or path, e.g. "Home.Street", for fields of nested structs)
+	// and named fields use the CSV field (header) name
+	//
+	// slice fields mapped by index range are written at their CSV field indices (the number of CSV fields of an open-ended index range is taken from the first struct written)
+	// and map fields mapped by index range, header pattern or header regex are written with the keys of the first struct written as headers - other fields mapped by
+	// header pattern or header regex cannot be written (and writing returns an error)
+	//
+	// the options can be any of csv.Comma, csv.QuotePolicy, csv.LineTerminator, csv.Header or csv.NoHeader
+	Writer(w io.Writer, options ...any) WriterContext[T]
+	// WriterContext returns a writer context for the mapper using the provided csv.Writer
+	//
+	// if the csv.Writer has no header set, the header is built from the mappings (as for Writer)
+	WriterContext(w *csv.Writer) WriterContext[T]
 	// Adapt creates a new Mapper from this mapper with struct field to CSV fields overridden
 	//
 	// Options from the original mapper are preserved unless overridden by the provided options
@@ -47,6 +66,8 @@ func NewMapper[T any](options ...any) (Mapper[T], error) {
 	}
 	if err := result.mapStruct(); err != nil {
 		return nil, err
+	} else if err = result.orderFieldSetters(); err != nil {
+		return nil, err
 	}
 	return result, nil
 }
@@ -61,16 +82,36 @@ func MustNewMapper[T any](options ...any) Mapper[T] {
 }
 
 type mapper[T any] struct {
-	ignoreUnknownFieldNames bool
-	defaultEmptyValues      bool
-	lineMapper              func(t *T, r *csv.Reader)
-	rawMapper               func(t *T, r []string)
-	rawDataMapper           func(t *T, r []byte)
-	csvFieldIndices         map[int]func(t *T, val string, quoted bool, defEmpties bool, record []string) error
-	csvFieldNames           map[string]func(t *T, val string, quoted bool, defEmpties bool, record []string) error
-	fieldMappings           map[string]any // int value is csv index, string value is csv header
-	fieldIndices            map[string][]int
-	fieldIndex              int // used only while inspecting struct fields
+	ignoreUnknownFieldNames  bool
+	disallowUnknownColumns   bool
+	disallowDuplicateHeaders bool
+	defaultEmptyValues       bool
+	lineMapper               func(t *T, r *csv.Reader)
+	rawMapper                func(t *T, r []string)
+	rawDataMapper            func(t *T, r []byte)
+	extraMapper              func(t *T, extras []csvColumn)
+	extraByHeader            bool
+	csvFieldIndices          map[int]func(t *T, val string, quoted bool, defEmpties bool, record []string) error
+	csvFieldNames            map[string]func(t *T, val string, quoted bool, defEmpties bool, record []string) error
+	csvFieldColumns          map[string]func(t *T, columns []csvColumn, defEmpties bool, record []string) error
+	csvFieldRanges           map[string]csvFieldRange[T]
+	fieldSetters             []fieldSetter[T] // the setters of all mapped struct fields (in struct field order)
+	headerRegexes            map[string]*regexp.Regexp
+	fieldMappings            map[string]any // int value is csv index, string value is csv header
+	fieldIndices             map[string][]int
+	fieldTags                map[string]fieldTag
+	fieldIndex               int // used only while inspecting struct fields
+	timeLayout               string
+	timeLocation             *time.Location
+	sliceSeparator           string
+	numberFormat             *NumberFormat
+	boolValues               *BoolValues
+	nullValues               map[string]bool
+	converters               map[reflect.Type]valueSetter
+	formatters               map[reflect.Type]valueGetter
+	enums                    map[reflect.Type][]string
+	headerNormalization      HeaderNormalization
+	headerNormalizer         HeaderNormalizer
 }
 
 func (m *mapper[T]) setOptions(options ...any) error {
@@ -79,8 +120,50 @@ func (m *mapper[T]) setOptions(options ...any) error {
 			switch option := o.(type) {
 			case IgnoreUnknownFieldNames:
 				m.ignoreUnknownFieldNames = bool(option)
+			case DisallowUnknownColumns:
+				m.disallowUnknownColumns = bool(option)
+			case DisallowDuplicateHeaders:
+				m.disallowDuplicateHeaders = bool(option)
 			case DefaultEmptyValues:
 				m.defaultEmptyValues = bool(option)
+			case DefaultTimeLayout:
+				m.timeLayout = string(option)
+			case TimeZone:
+				loc, err := time.LoadLocation(string(option))
+				if err != nil {
+					return fmt.Errorf("invalid time zone option %q", string(option))
+				}
+				m.timeLocation = loc
+			case Converter:
+				if m.converters == nil {
+					m.converters = make(map[reflect.Type]valueSetter)
+				}
+				m.converters[option.typ] = option.setter
+			case Enum:
+				if m.enums == nil {
+					m.enums = make(map[reflect.Type][]string)
+				}
+				m.enums[option.typ] = option.values
+			case Formatter:
+				if m.formatters == nil {
+					m.formatters = make(map[reflect.Type]valueGetter)
+				}
+				m.formatters[option.typ] = option.getter
+			case NullValues:
+				m.nullValues = nullTokens(option)
+			case BoolValues:
+				m.boolValues = &option
+			case NumberFormat:
+				if err := option.validate(); err != nil {
+					return err
+				}
+				m.numberFormat = &option
+			case DefaultSliceSeparator:
+				m.sliceSeparator = string(option)
+			case HeaderNormalization:
+				m.headerNormalization = option
+			case HeaderNormalizer:
+				m.headerNormalizer = option
 			default:
 				return fmt.Errorf("unknown option type: %T", option)
 			}
@@ -101,30 +184,117 @@ func (m *mapper[T]) ReaderContext(r *csv.Reader, postProcessor func(row *T) erro
 	}
 }
 
+func (m *mapper[T]) Writer(w io.Writer, options ...any) WriterContext[T] {
+	return m.WriterContext(csv.NewWriter(w, options...))
+}
+
+func (m *mapper[T]) WriterContext(w *csv.Writer) WriterContext[T] {
+	fields, err := m.writerFields()
+	return newWriterContext[T](w, fields, err)
+}
+
+func (m *mapper[T]) writerFields() ([]writerField[T], error) {
+	var t T
+	rt := reflect.TypeOf(t)
+	mappings := m.Mappings()
+	result := make([]writerField[T], 0, len(mappings))
+	for _, mapping := range mappings {
+		if name := mapping.CsvFieldName; isIndexRange(name) {
+			ir, err := parseIndexRange(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w (field name: %q)", err, mapping.FieldName)
+			}
+			size := 0
+			if ir.to != 0 {
+				size = ir.to - ir.from + 1
+			}
+			columns, err := m.fieldColumnsGetter(mapping.FieldName, size)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, writerField[T]{index: ir.from, size: size, columns: columns})
+		} else if isHeaderRegex(name) || isHeaderPattern(name) {
+			// headers matching a regex (or pattern) are only known from the keys of map fields...
+			if !m.isColumnsField(mapping.FieldName, name) || rt.FieldByIndex(m.fieldIndices[mapping.FieldName]).Type.Kind() != reflect.Map {
+				return nil, fmt.Errorf("field with csv header %q cannot be written - expected to be map (field name: %q)", name, mapping.FieldName)
+			}
+			columns, err := m.fieldColumnsGetter(mapping.FieldName, 0)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, writerField[T]{columns: columns})
+		} else {
+			getter, err := m.fieldGetter(mapping.FieldName)
+			if err != nil {
+				return nil, fmt.Errorf("%w (field name: %q)", err, mapping.FieldName)
+			}
+			if mapping.CsvFieldIndex > 0 {
+				// the field path is used as the header - so that fields of different nested structs (of the same type) have unique headers...
+				result = append(result, writerField[T]{index: mapping.CsvFieldIndex, column: writerColumn[T]{header: mapping.FieldName, getter: getter}})
+			} else {
+				// aliased headers are written using the first alias (and without any occurrence suffix)...
+				hdr, _, _ := headerOccurrence(headerAliases(name)[0])
+				result = append(result, writerField[T]{column: writerColumn[T]{header: hdr, getter: getter}})
+			}
+		}
+	}
+	return result, nil
+}
+
 func (m *mapper[T]) Adapt(clear bool, mappings OverrideMappings, options ...any) (Mapper[T], error) {
 	result := &mapper[T]{
-		ignoreUnknownFieldNames: m.ignoreUnknownFieldNames,
-		lineMapper:              m.lineMapper,
-		rawMapper:               m.rawMapper,
-		rawDataMapper:           m.rawDataMapper,
-		fieldIndices:            m.fieldIndices,
-		csvFieldIndices:         make(map[int]func(t *T, val string, quoted bool, defEmpties bool, record []string) error),
-		csvFieldNames:           make(map[string]func(t *T, val string, quoted bool, defEmpties bool, record []string) error),
-		fieldMappings:           make(map[string]any),
+		ignoreUnknownFieldNames:  m.ignoreUnknownFieldNames,
+		disallowUnknownColumns:   m.disallowUnknownColumns,
+		disallowDuplicateHeaders: m.disallowDuplicateHeaders,
+		defaultEmptyValues:       m.defaultEmptyValues,
+		lineMapper:               m.lineMapper,
+		rawMapper:                m.rawMapper,
+		rawDataMapper:            m.rawDataMapper,
+		extraMapper:              m.extraMapper,
+		extraByHeader:            m.extraByHeader,
+		fieldIndices:             m.fieldIndices,
+		fieldTags:                m.fieldTags,
+		timeLayout:               m.timeLayout,
+		timeLocation:             m.timeLocation,
+		sliceSeparator:           m.sliceSeparator,
+		numberFormat:             m.numberFormat,
+		boolValues:               m.boolValues,
+		nullValues:               m.nullValues,
+		converters:               cloneMap(m.converters),
+		formatters:               cloneMap(m.formatters),
+		enums:                    cloneMap(m.enums),
+		headerNormalization:      m.headerNormalization,
+		headerNormalizer:         m.headerNormalizer,
+		csvFieldIndices:          make(map[int]func(t *T, val string, quoted bool, defEmpties bool, record []string) error),
+		csvFieldNames:            make(map[string]func(t *T, val string, quoted bool, defEmpties bool, record []string) error),
+		csvFieldColumns:          make(map[string]func(t *T, columns []csvColumn, defEmpties bool, record []string) error),
+		csvFieldRanges:           make(map[string]csvFieldRange[T]),
+		headerRegexes:            cloneMap(m.headerRegexes),
+		fieldMappings:            make(map[string]any),
 	}
 	if err := result.setOptions(options...); err != nil {
 		return nil, err
 	}
 	if !clear {
-		result.csvFieldIndices = cloneMap(m.csvFieldIndices)
-		result.csvFieldNames = cloneMap(m.csvFieldNames)
+		// setters are rebuilt (rather than cloned) - as options may have changed...
 		result.fieldMappings = cloneMap(m.fieldMappings)
+		for fldName, fm := range result.fieldMappings {
+			switch k := fm.(type) {
+			case int:
+				setter, err := result.fieldSetter(fldName)
+				if err != nil {
+					return nil, err
+				}
+				result.csvFieldIndices[k] = setter
+			case string:
+				if err := result.mapFieldName(fldName, k); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
-	var t T
-	to := reflect.TypeOf(t)
 	for _, mapping := range mappings {
-		fieldPath, ok := m.fieldIndices[mapping.FieldName]
-		if !ok {
+		if _, ok := m.fieldIndices[mapping.FieldName]; !ok {
 			return nil, fmt.Errorf("field %q not found", mapping.FieldName)
 		}
 		switch {
@@ -134,7 +304,7 @@ func (m *mapper[T]) Adapt(clear bool, mappings OverrideMappings, options ...any)
 			delete(result.fieldMappings, mapping.FieldName)
 		case strings.HasPrefix(mapping.CsvFieldName, "-"):
 			// remove name mapping...
-			delete(result.csvFieldNames, strings.TrimPrefix(mapping.CsvFieldName, "-"))
+			result.unmapFieldName(strings.TrimPrefix(mapping.CsvFieldName, "-"))
 			delete(result.fieldMappings, mapping.FieldName)
 		case mapping.CsvFieldIndex > 0:
 			// re-map by index...
@@ -143,15 +313,17 @@ func (m *mapper[T]) Adapt(clear bool, mappings OverrideMappings, options ...any)
 				case int:
 					delete(result.csvFieldIndices, k)
 				case string:
-					delete(result.csvFieldNames, k)
+					result.unmapFieldName(k)
 				}
 			}
-			result.fieldMappings[mapping.FieldName] = mapping.CsvFieldIndex
-			var err error
-			fld := to.FieldByIndex(fieldPath)
-			if result.csvFieldIndices[mapping.CsvFieldIndex], err = buildSetter[T](fieldPath, fld); err != nil {
+			setter, err := result.fieldSetter(mapping.FieldName)
+			if err != nil {
 				return nil, err
+			} else if result.isIndexMapped(mapping.CsvFieldIndex) {
+				return nil, fmt.Errorf("field with csv index %d already mapped  (field name: %q)", mapping.CsvFieldIndex, mapping.FieldName)
 			}
+			result.fieldMappings[mapping.FieldName] = mapping.CsvFieldIndex
+			result.csvFieldIndices[mapping.CsvFieldIndex] = setter
 		case mapping.CsvFieldName != "":
 			// re-map by name...
 			if exm, ok := result.fieldMappings[mapping.FieldName]; ok {
@@ -159,17 +331,17 @@ func (m *mapper[T]) Adapt(clear bool, mappings OverrideMappings, options ...any)
 				case int:
 					delete(result.csvFieldIndices, k)
 				case string:
-					delete(result.csvFieldNames, k)
+					result.unmapFieldName(k)
 				}
 			}
-			result.fieldMappings[mapping.FieldName] = mapping.CsvFieldName
-			var err error
-			fld := to.FieldByIndex(fieldPath)
-			if result.csvFieldNames[mapping.CsvFieldName], err = buildSetter[T](fieldPath, fld); err != nil {
+			if err := result.mapFieldName(mapping.FieldName, mapping.CsvFieldName); err != nil {
 				return nil, err
 			}
 		}
 	}
+	if err := result.orderFieldSetters(); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -234,30 +406,100 @@ func (m *mapper[T]) mapStruct() error {
 	}
 	m.fieldIndex = 1
 	m.csvFieldNames = make(map[string]func(t *T, val string, quoted bool, defEmpties bool, record []string) error)
+	m.csvFieldColumns = make(map[string]func(t *T, columns []csvColumn, defEmpties bool, record []string) error)
+	m.csvFieldRanges = make(map[string]csvFieldRange[T])
+	m.headerRegexes = make(map[string]*regexp.Regexp)
 	m.csvFieldIndices = make(map[int]func(t *T, val string, quoted bool, defEmpties bool, record []string) error)
 	m.fieldMappings = make(map[string]any)
 	m.fieldIndices = make(map[string][]int)
-	return m.visitStructFields(rt, nil, nil)
+	m.fieldTags = make(map[string]fieldTag)
+	return m.visitStructFields(rt, nil, nil, "")
+}
+
+func (m *mapper[T]) fieldSetter(fldName string) (func(t *T, val string, quoted bool, defEmpties bool, record []string) error, error) {
+	opts, err := m.fieldOptions(fldName)
+	if err != nil {
+		return nil, err
+	} else if opts.dropTrailing {
+		return nil, fmt.Errorf("csv tag option %q only supported for fields mapped to multiple csv fields (field name: %q)", csvTagOptionDropTrailing, fldName)
+	} else if opts.omitEmpty {
+		return nil, fmt.Errorf("csv tag option %q only supported for fields mapped to multiple csv fields (field name: %q)", csvTagOptionOmitEmpty, fldName)
+	}
+	var t T
+	fieldPath := m.fieldIndices[fldName]
+	fld := reflect.TypeOf(t).FieldByIndex(fieldPath)
+	setter, err := buildSetter[T](fieldPath, fld, opts)
+	if err != nil {
+		return nil, err
+	} else if opts.defaultValue != nil {
+		// the default value is checked up front (rather than when it is first used reading a CSV line)...
+		vs, _ := buildValueSetter(fld.Type, opts)
+		if err = vs(reflect.New(fld.Type).Elem(), *opts.defaultValue, false, false, nil); err != nil {
+			return nil, fmt.Errorf("invalid csv tag option %s=%q - %w (field name: %q)", csvTagOptionDefault, *opts.defaultValue, err, fldName)
+		}
+	}
+	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
+		if err := setter(t, val, quoted, defEmpties, record); err != nil {
+			// the reader completes the csv field details (line, column, index and header)...
+			return &FieldError{Field: fldName, Value: val, Type: fld.Type, Err: err}
+		}
+		return nil
+	}, nil
+}
+
+func (m *mapper[T]) fieldGetter(fldName string) (func(t *T, record []string) (string, csv.QuotePolicy, error), error) {
+	opts, err := m.fieldOptions(fldName)
+	if err != nil {
+		return nil, err
+	}
+	var t T
+	fieldPath := m.fieldIndices[fldName]
+	return buildGetter[T](fieldPath, reflect.TypeOf(t).FieldByIndex(fieldPath), opts)
+}
+
+func (m *mapper[T]) fieldColumnsGetter(fldName string, size int) (func(first *T) ([]writerColumn[T], func(t *T) error), error) {
+	opts, err := m.fieldOptions(fldName)
+	if err != nil {
+		return nil, err
+	}
+	var t T
+	fieldPath := m.fieldIndices[fldName]
+	getter, err := buildColumnsGetter[T](fieldPath, reflect.TypeOf(t).FieldByIndex(fieldPath), opts, fldName, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w (field name: %q)", err, fldName)
+	}
+	return func(first *T) ([]writerColumn[T], func(t *T) error) {
+		columns, check := getter(first)
+		return columns, func(t *T) error {
+			if err := check(t); err != nil {
+				return fmt.Errorf("%w (field name: %q)", err, fldName)
+			}
+			return nil
+		}
+	}, nil
 }
 
-func (m *mapper[T]) visitStructFields(rt reflect.Type, fieldPath []int, namePath []string) (err error) {
+func (m *mapper[T]) visitStructFields(rt reflect.Type, fieldPath []int, namePath []string, headerPrefix string) (err error) {
 	for i := 0; i < rt.NumField(); i++ {
 		fld := rt.Field(i)
 		if !fld.IsExported() {
 			continue
 		}
 		currentPath := append(fieldPath, i)
-		if fld.Type.Kind() == reflect.Struct {
-			if fld.Anonymous {
-				if err = m.visitStructFields(fld.Type, currentPath, namePath); err != nil {
-					return err
+		if st, ok := nestedStructType(fld.Type); ok && !m.isConvertedType(st) && !m.isConvertedType(fld.Type) {
+			if m.isRecursiveStruct(st, fieldPath) {
+				return fmt.Errorf("recursive struct field not supported (field name: %q)", strings.Join(append(namePath, fld.Name), "."))
+			}
+			if fld.Anonymous || !isUnmarshalerType(fld.Type) {
+				nestedPrefix, err := nestedHeaderPrefix(fld, headerPrefix)
+				if err != nil {
+					return fmt.Errorf("%w (field name: %q)", err, strings.Join(append(namePath, fld.Name), "."))
 				}
-				continue
-			} else if !isUnmarshalerType(fld.Type) {
-				if _, ok := fld.Tag.Lookup(csvTagName); ok {
-					return fmt.Errorf("nested struct field cannot have %q tag", csvTagName)
+				nestedPath := namePath
+				if !fld.Anonymous {
+					nestedPath = append(namePath, fld.Name)
 				}
-				if err = m.visitStructFields(fld.Type, currentPath, append(namePath, fld.Name)); err != nil {
+				if err = m.visitStructFields(st, currentPath, nestedPath, nestedPrefix); err != nil {
 					return err
 				}
 				continue
@@ -265,7 +507,15 @@ func (m *mapper[T]) visitStructFields(rt reflect.Type, fieldPath []int, namePath
 		}
 		fldName := strings.Join(append(namePath, fld.Name), ".")
 		m.fieldIndices[fldName] = append([]int{}, currentPath...)
-		if tag, ok := fld.Tag.Lookup(csvTagName); ok {
+		if tagValue, ok := fld.Tag.Lookup(csvTagName); ok {
+			ft, err := parseFieldTag(tagValue)
+			if err != nil {
+				return fmt.Errorf("%w (field name: %q)", err, fldName)
+			} else if _, ok := ft.options[csvTagOptionPrefix]; ok {
+				return fmt.Errorf("csv tag option %q only supported for nested struct fields (field name: %q)", csvTagOptionPrefix, fldName)
+			}
+			m.fieldTags[fldName] = ft
+			tag := ft.name
 			switch tag {
 			case csvTagLine:
 				if fld.Type.Kind() != reflect.Int {
@@ -273,36 +523,52 @@ func (m *mapper[T]) visitStructFields(rt reflect.Type, fieldPath []int, namePath
 				}
 				m.lineMapper = func(t *T, r *csv.Reader) {
 					ln := r.CurrentLine()
-					reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetInt(int64(ln))
+					fv, _ := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, true)
+					fv.SetInt(int64(ln))
 				}
 			case csvTagRaw:
 				if fld.Type.Kind() != reflect.Slice || fld.Type.Elem().Kind() != reflect.String {
 					return fmt.Errorf("field with %q expected to be slice of strings (field name: %q)", csvTagRaw, fldName)
 				}
 				m.rawMapper = func(t *T, r []string) {
-					reflect.ValueOf(t).Elem().FieldByIndex(currentPath).Set(reflect.ValueOf(r))
+					fv, _ := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, true)
+					fv.Set(reflect.ValueOf(r))
 				}
 			case csvTagRawData:
 				if fld.Type.Kind() == reflect.Slice && fld.Type.Elem().Kind() == reflect.Uint8 {
 					m.rawDataMapper = func(t *T, r []byte) {
-						reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetBytes(r)
+						fv, _ := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, true)
+						fv.SetBytes(r)
 					}
 				} else if fld.Type.Kind() == reflect.String {
 					m.rawDataMapper = func(t *T, r []byte) {
-						reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetString(string(r))
+						fv, _ := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, true)
+						fv.SetString(string(r))
 					}
 				} else {
 					return fmt.Errorf("field with %q expected to be slice of bytes or string (field name: %q)", csvTagRawData, fldName)
 				}
+			case csvTagExtra:
+				var ok bool
+				if m.extraMapper, m.extraByHeader, ok = buildExtraMapper[T](currentPath, fld); !ok {
+					return fmt.Errorf("field with %q expected to be map[string]string or map[int]string (field name: %q)", csvTagExtra, fldName)
+				}
 			default:
-				if strings.HasPrefix(tag, "[") && strings.HasSuffix(tag, "]") {
+				if headerPrefix != "" && (isIndexRange(tag) || (strings.HasPrefix(tag, "[") && strings.HasSuffix(tag, "]"))) {
+					return fmt.Errorf("csv field index %s not supported within nested struct with %q option (field name: %q)", tag, csvTagOptionPrefix, fldName)
+				} else if isIndexRange(tag) {
+					// specified by index range
+					if err = m.mapFieldName(fldName, tag); err != nil {
+						return err
+					}
+				} else if strings.HasPrefix(tag, "[") && strings.HasSuffix(tag, "]") {
 					// specified by index
 					tag = tag[1 : len(tag)-1]
 					if idx, err := strconv.Atoi(tag); err == nil && idx > 0 {
-						if _, exists := m.csvFieldIndices[idx]; exists {
+						if m.isIndexMapped(idx) {
 							return fmt.Errorf("field with csv index %d already mapped  (field name: %q)", idx, fldName)
 						}
-						if m.csvFieldIndices[idx], err = buildSetter[T](currentPath, fld); err != nil {
+						if m.csvFieldIndices[idx], err = m.fieldSetter(fldName); err != nil {
 							return err
 						}
 						m.fieldMappings[fldName] = idx
@@ -313,21 +579,252 @@ func (m *mapper[T]) visitStructFields(rt reflect.Type, fieldPath []int, namePath
 					}
 				} else if tag != "-" && tag != "" {
 					// specified by name
-					if _, exists := m.csvFieldNames[tag]; exists {
+					tag = prefixHeader(headerPrefix, tag)
+					if m.isNameMapped(tag) {
 						return fmt.Errorf("field with csv name %q already mapped  (field name: %q)", tag, fldName)
 					}
-					if m.csvFieldNames[tag], err = buildSetter[T](currentPath, fld); err != nil {
+					if err = m.mapFieldName(fldName, tag); err != nil {
+						return err
+					}
+				} else if tag == "" && len(ft.options) > 0 {
+					// options only - implied index (or prefixed field name)
+					if err = m.mapImplied(fldName, fld.Name, headerPrefix); err != nil {
 						return err
 					}
-					m.fieldMappings[fldName] = tag
 				}
 			}
-		} else {
-			if m.csvFieldIndices[m.fieldIndex], err = buildSetter[T](currentPath, fld); err != nil {
-				return err
-			}
-			m.fieldMappings[fldName] = m.fieldIndex
-			m.fieldIndex++
+		} else if err = m.mapImplied(fldName, fld.Name, headerPrefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedHeaderPrefix returns the header prefix for the fields of a nested (or embedded) struct field - as set by the "prefix" tag option (e.g. `csv:"billing_,prefix"`)
+//
+// prefixes of nested structs within nested structs are accumulated - untagged fields of prefixed structs are mapped by prefixed field name (see mapImplied)
+func nestedHeaderPrefix(fld reflect.StructField, headerPrefix string) (string, error) {
+	tagValue, ok := fld.Tag.Lookup(csvTagName)
+	if !ok {
+		return headerPrefix, nil
+	}
+	ft, err := parseFieldTag(tagValue)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := ft.options[csvTagOptionPrefix]; !ok {
+		if fld.Anonymous {
+			// tags on embedded structs (without prefix option) are ignored...
+			return headerPrefix, nil
+		}
+		return "", fmt.Errorf("nested struct field cannot have %q tag (other than with %q option)", csvTagName, csvTagOptionPrefix)
+	} else if len(ft.options) > 1 {
+		return "", fmt.Errorf("csv tag option %q cannot be used with other options", csvTagOptionPrefix)
+	} else if ft.name == "" {
+		return "", fmt.Errorf("csv tag option %q requires a header prefix", csvTagOptionPrefix)
+	}
+	return headerPrefix + ft.name, nil
+}
+
+// nestedStructType returns the struct type of a nested (or embedded) struct field - or pointer to struct field (which is allocated only when any of its mapped csv fields are non-empty)
+func nestedStructType(ft reflect.Type) (reflect.Type, bool) {
+	if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && !isUnmarshalerType(ft) {
+		return ft.Elem(), true
+	}
+	return ft, ft.Kind() == reflect.Struct
+}
+
+// isRecursiveStruct determines whether the nested struct type is the same as the struct type of any of its ancestors (i.e. a pointer to a struct of the same type)
+func (m *mapper[T]) isRecursiveStruct(st reflect.Type, fieldPath []int) bool {
+	var t T
+	rt := reflect.TypeOf(t)
+	if rt == st {
+		return true
+	}
+	for i := range fieldPath {
+		if ft, _ := nestedStructType(rt.FieldByIndex(fieldPath[:i+1]).Type); ft == st {
+			return true
+		}
+	}
+	return false
+}
+
+// mapFieldName maps the field to the csv header name - fields mapped by header regex (or header pattern) that are slices or maps are set from all matching csv fields
+//
+// the name may also be an index range (e.g. "[5:12]") - where the slice field is set from all csv fields in that range
+func (m *mapper[T]) mapFieldName(fldName string, name string) (err error) {
+	if isIndexRange(name) {
+		return m.mapIndexRange(fldName, name)
+	} else if err = m.compileHeaderRegex(name); err != nil {
+		return fmt.Errorf("%w (field name: %q)", err, fldName)
+	}
+	if m.isColumnsField(fldName, name) {
+		if m.csvFieldColumns[name], err = m.fieldColumnsSetter(fldName); err != nil {
+			return err
+		}
+	} else if m.csvFieldNames[name], err = m.fieldSetter(fldName); err != nil {
+		return err
+	}
+	m.fieldMappings[fldName] = name
+	return nil
+}
+
+func (m *mapper[T]) mapIndexRange(fldName string, name string) (err error) {
+	ir, err := parseIndexRange(name)
+	if err != nil {
+		return fmt.Errorf("%w (field name: %q)", err, fldName)
+	}
+	var t T
+	ft := reflect.TypeOf(t).FieldByIndex(m.fieldIndices[fldName]).Type
+	if (ft.Kind() != reflect.Slice && ft.Kind() != reflect.Map) || isUnmarshalerType(ft) {
+		return fmt.Errorf("field with csv index range %s expected to be slice or map (field name: %q)", name, fldName)
+	}
+	if m.isIndexRangeMapped(ir) {
+		return fmt.Errorf("field with csv index range %s overlaps already mapped csv fields (field name: %q)", name, fldName)
+	}
+	fr := csvFieldRange[T]{indexRange: ir, byHeader: ft.Kind() == reflect.Map}
+	if fr.setter, err = m.fieldColumnsSetter(fldName); err != nil {
+		return err
+	}
+	m.csvFieldRanges[name] = fr
+	m.fieldMappings[fldName] = name
+	if ir.to != 0 {
+		// following fields follow this index range
+		m.fieldIndex = ir.to + 1
+	}
+	return nil
+}
+
+func (m *mapper[T]) unmapFieldName(name string) {
+	delete(m.csvFieldNames, name)
+	delete(m.csvFieldColumns, name)
+	delete(m.csvFieldRanges, name)
+}
+
+func (m *mapper[T]) isNameMapped(name string) (exists bool) {
+	if _, exists = m.csvFieldNames[name]; !exists {
+		if _, exists = m.csvFieldColumns[name]; !exists {
+			_, exists = m.csvFieldRanges[name]
+		}
+	}
+	return exists
+}
+
+// isIndexMapped determines whether the (1 based) csv field index is mapped to a struct field - either by index or index range
+func (m *mapper[T]) isIndexMapped(idx int) bool {
+	if _, ok := m.csvFieldIndices[idx]; ok {
+		return true
+	}
+	for _, fr := range m.csvFieldRanges {
+		if fr.contains(idx) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexRangeMapped determines whether any (1 based) csv field index within the range is mapped to a struct field - either by index or index range
+func (m *mapper[T]) isIndexRangeMapped(ir indexRange) bool {
+	for idx := range m.csvFieldIndices {
+		if ir.contains(idx) {
+			return true
+		}
+	}
+	for _, fr := range m.csvFieldRanges {
+		if fr.overlaps(ir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mapper[T]) isColumnsField(fldName string, name string) bool {
+	var t T
+	ft := reflect.TypeOf(t).FieldByIndex(m.fieldIndices[fldName]).Type
+	return (isHeaderRegex(name) || isHeaderPattern(name)) && (ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map) && !isUnmarshalerType(ft)
+}
+
+func (m *mapper[T]) fieldColumnsSetter(fldName string) (func(t *T, columns []csvColumn, defEmpties bool, record []string) error, error) {
+	opts, err := m.fieldOptions(fldName)
+	if err != nil {
+		return nil, err
+	}
+	var t T
+	fieldPath := m.fieldIndices[fldName]
+	setter, err := buildColumnsSetter[T](fieldPath, reflect.TypeOf(t).FieldByIndex(fieldPath), opts)
+	if err != nil {
+		return nil, err
+	}
+	return func(t *T, columns []csvColumn, defEmpties bool, record []string) error {
+		err := setter(t, columns, defEmpties, record)
+		var fe *FieldError
+		if errors.As(err, &fe) {
+			fe.Field = fldName
+		}
+		return err
+	}, nil
+}
+
+// mapImplied maps a field that has no csv index or header name - by implied index or, within a nested struct with a header prefix, by the prefixed struct field name (e.g. "billing_Street")
+func (m *mapper[T]) mapImplied(fldName string, name string, headerPrefix string) error {
+	if headerPrefix == "" {
+		return m.mapImpliedIndex(fldName)
+	}
+	tag := headerPrefix + name
+	if m.isNameMapped(tag) {
+		return fmt.Errorf("field with csv name %q already mapped  (field name: %q)", tag, fldName)
+	}
+	return m.mapFieldName(fldName, tag)
+}
+
+func (m *mapper[T]) mapImpliedIndex(fldName string) (err error) {
+	if m.isIndexMapped(m.fieldIndex) {
+		// e.g. following an open-ended index range (such as "[5:]")...
+		return fmt.Errorf("field with csv index %d already mapped  (field name: %q)", m.fieldIndex, fldName)
+	}
+	if m.csvFieldIndices[m.fieldIndex], err = m.fieldSetter(fldName); err != nil {
+		return err
+	}
+	m.fieldMappings[fldName] = m.fieldIndex
+	m.fieldIndex++
+	return nil
+}
+
+// fieldSetter is the setter for a mapped struct field - set from a single csv field (by index or header name) or from multiple csv fields (by header regex, header pattern or index range)
+type fieldSetter[T any] struct {
+	index   int    // 1 based csv field index (zero if not mapped by index)
+	name    string // csv header name, header regex, header pattern or index range (empty if mapped by index)
+	setter  func(t *T, val string, quoted bool, defEmpties bool, record []string) error
+	columns func(t *T, columns []csvColumn, defEmpties bool, record []string) error
+	fr      *csvFieldRange[T]
+	opts    *fieldOptions
+	path    []int
+	lazy    bool // the struct field is within a pointer to a nested (or embedded) struct - which is only allocated when any of its mapped csv fields are non-empty
+}
+
+// orderFieldSetters builds the field setters in struct field order - so that reading a CSV line is deterministic (e.g. which error is reported for a line with multiple bad values)
+func (m *mapper[T]) orderFieldSetters() (err error) {
+	var t T
+	rt := reflect.TypeOf(t)
+	mappings := m.Mappings()
+	m.fieldSetters = make([]fieldSetter[T], 0, len(mappings))
+	for _, mapping := range mappings {
+		fs := fieldSetter[T]{index: mapping.CsvFieldIndex, name: mapping.CsvFieldName, path: m.fieldIndices[mapping.FieldName]}
+		if fs.opts, err = m.fieldOptions(mapping.FieldName); err != nil {
+			return err
+		}
+		for i := 1; i < len(fs.path) && !fs.lazy; i++ {
+			fs.lazy = rt.FieldByIndex(fs.path[:i]).Type.Kind() == reflect.Ptr
+		}
+		if fs.index > 0 {
+			fs.setter = m.csvFieldIndices[fs.index]
+		} else if fr, ok := m.csvFieldRanges[fs.name]; ok {
+			fs.fr = &fr
+		} else if fs.columns, ok = m.csvFieldColumns[fs.name]; !ok {
+			fs.setter = m.csvFieldNames[fs.name]
+		}
+		if fs.setter != nil || fs.columns != nil || fs.fr != nil {
+			m.fieldSetters = append(m.fieldSetters, fs)
 		}
 	}
 	return nil
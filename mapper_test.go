@@ -1,9 +1,13 @@
 package csvamp
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/require"
+	"math/big"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 type Unmarshalable struct {
@@ -180,6 +184,67 @@ func TestNewMapper(t *testing.T) {
 		require.True(t, rm.ignoreUnknownFieldNames)
 		require.True(t, rm.defaultEmptyValues)
 	})
+	t.Run("With time options", func(t *testing.T) {
+		type testStruct struct {
+			Foo time.Time
+		}
+		m, err := NewMapper[testStruct](DefaultTimeLayout("2006-01-02"), TimeZone("Europe/London"))
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		rm, ok := m.(*mapper[testStruct])
+		require.True(t, ok)
+		require.Equal(t, "2006-01-02", rm.timeLayout)
+		require.Equal(t, "Europe/London", rm.timeLocation.String())
+	})
+	t.Run("With header normalization options", func(t *testing.T) {
+		type testStruct struct {
+			Foo string
+		}
+		m, err := NewMapper[testStruct](HeaderFoldCase|HeaderTrimSpace, HeaderNormalizer(strings.ToUpper))
+		require.NoError(t, err)
+		rm, ok := m.(*mapper[testStruct])
+		require.True(t, ok)
+		require.Equal(t, HeaderFoldCase|HeaderTrimSpace, rm.headerNormalization)
+		require.NotNil(t, rm.headerNormalizer)
+		am, err := m.Adapt(false, nil)
+		require.NoError(t, err)
+		rm, ok = am.(*mapper[testStruct])
+		require.True(t, ok)
+		require.Equal(t, HeaderFoldCase|HeaderTrimSpace, rm.headerNormalization)
+		require.NotNil(t, rm.headerNormalizer)
+	})
+	t.Run("Tag with options", func(t *testing.T) {
+		type testStruct struct {
+			Foo time.Time `csv:"foo,layout=2006-01-02,tz=Europe/London"`
+			Bar time.Time `csv:",layout=2006-01-02"`
+			Baz time.Time `csv:"[3],layout=02/01/2006"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		rm, ok := m.(*mapper[testStruct])
+		require.True(t, ok)
+		require.Len(t, rm.fieldMappings, 3)
+		require.Equal(t, "foo", rm.fieldMappings["Foo"])
+		require.Equal(t, 1, rm.fieldMappings["Bar"])
+		require.Equal(t, 3, rm.fieldMappings["Baz"])
+		require.Equal(t, map[string]string{"layout": "2006-01-02", "tz": "Europe/London"}, rm.fieldTags["Foo"].options)
+	})
+	t.Run("Tag with quoted values", func(t *testing.T) {
+		type testStruct struct {
+			Foo time.Time `csv:"'foo, bar',layout='Jan 2, 2006'"`
+			Bar string    `csv:"Driver's licence,trim"`
+			Baz string    `csv:"baz,default='it''s'"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		rm, ok := m.(*mapper[testStruct])
+		require.True(t, ok)
+		require.Equal(t, "foo, bar", rm.fieldMappings["Foo"])
+		require.Equal(t, map[string]string{"layout": "Jan 2, 2006"}, rm.fieldTags["Foo"].options)
+		require.Equal(t, "Driver's licence", rm.fieldMappings["Bar"])
+		require.Equal(t, map[string]string{"trim": ""}, rm.fieldTags["Bar"].options)
+		require.Equal(t, map[string]string{"default": "it's"}, rm.fieldTags["Baz"].options)
+	})
 }
 
 func TestNewMapper_Errors(t *testing.T) {
@@ -215,6 +280,14 @@ func TestNewMapper_Errors(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "expected to be slice of strings")
 	})
+	t.Run("Bad field type for [extra]", func(t *testing.T) {
+		type testStruct struct {
+			Extra map[string]int `csv:"[extra]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expected to be map[string]string or map[int]string")
+	})
 	t.Run("Bad field type for [rawData]", func(t *testing.T) {
 		type testStruct struct {
 			RawData int `csv:"[rawData]"`
@@ -258,6 +331,30 @@ func TestNewMapper_Errors(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "nested struct field cannot have")
 	})
+	t.Run("Bad nested struct prefix", func(t *testing.T) {
+		type testStruct struct {
+			Foo struct{} `csv:",prefix"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "csv tag option \"prefix\" requires a header prefix (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Bad nested struct prefix options", func(t *testing.T) {
+		type testStruct struct {
+			Foo struct{} `csv:"foo_,prefix,trim"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "csv tag option \"prefix\" cannot be used with other options (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Prefix on non-struct field", func(t *testing.T) {
+		type testStruct struct {
+			Foo string `csv:"foo_,prefix"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "csv tag option \"prefix\" only supported for nested struct fields (field name: \"Foo\")", err.Error())
+	})
 	t.Run("Unsupported struct field type (by implied index)", func(t *testing.T) {
 		type testStruct struct {
 			Foo []struct{}
@@ -314,6 +411,245 @@ func TestNewMapper_Errors(t *testing.T) {
 		require.Contains(t, err.Error(), "field with csv index ")
 		require.Contains(t, err.Error(), " already mapped")
 	})
+	t.Run("Bad time zone option", func(t *testing.T) {
+		type testStruct struct {
+			Foo string
+		}
+		_, err := NewMapper[testStruct](TimeZone("Not/A_Zone"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid time zone option")
+	})
+	t.Run("Bad number format option", func(t *testing.T) {
+		type testStruct struct {
+			Foo float64
+		}
+		_, err := NewMapper[testStruct](NumberFormat{GroupingSeparator: "."})
+		require.Error(t, err)
+		require.Equal(t, `number format grouping separator "." cannot be the same as the decimal separator`, err.Error())
+		_, err = NewMapper[testStruct](NumberFormat{DecimalSeparator: ",", GroupingSeparator: ","})
+		require.Error(t, err)
+		_, err = NewMapper[testStruct](NumberFormat{DecimalSeparator: ",", GroupingSeparator: "."})
+		require.NoError(t, err)
+	})
+	t.Run("Unknown tag option", func(t *testing.T) {
+		type testStruct struct {
+			Foo string `csv:"foo,unknown=1"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown csv tag option \"unknown\"")
+	})
+	t.Run("Tag options not supported for field type", func(t *testing.T) {
+		testCases := []struct {
+			tag       string
+			typ       reflect.Type
+			expectErr string
+		}{
+			{tag: "Name,sep=;", typ: reflect.TypeOf(""), expectErr: `csv tag option "sep" only supported for slice fields`},
+			{tag: "Name,sep=;", typ: reflect.TypeOf([]byte{}), expectErr: `csv tag option "sep" only supported for slice fields`},
+			{tag: "Name,layout=2006", typ: reflect.TypeOf(""), expectErr: `csv tag option "layout" only supported for time fields`},
+			{tag: "Age,tz=UTC", typ: reflect.TypeOf((*int)(nil)), expectErr: `csv tag option "tz" only supported for time fields`},
+			{tag: "Ok,prec=10", typ: reflect.TypeOf(false), expectErr: `csv tag option "prec" only supported for big.Float fields`},
+			{tag: "Vals,false=N", typ: reflect.TypeOf([]float64{}), expectErr: `csv tag option "false" only supported for bool fields`},
+			{tag: "Data,enum=a|b", typ: reflect.TypeOf([]byte{}), expectErr: `csv tag option "enum" only supported for non-[]byte fields`},
+			{tag: "Vals,sep=;,prec=10", typ: reflect.TypeOf(&[]*big.Float{})},
+			{tag: "Dates,layout=2006,tz=UTC", typ: reflect.TypeOf(map[string]*time.Time{})},
+			{tag: "Id,base=16", typ: reflect.TypeOf(uint8(0))},
+			{tag: "Flags,true=Y,false=N,sep=;", typ: reflect.TypeOf([]bool{})},
+		}
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("[%d]%s", i+1, tc.tag), func(t *testing.T) {
+				ft, err := parseFieldTag(tc.tag)
+				require.NoError(t, err)
+				err = ft.validateForType(tc.typ)
+				if tc.expectErr != "" {
+					require.Error(t, err)
+					require.Equal(t, tc.expectErr, err.Error())
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+		type testStruct struct {
+			Name string `csv:"Name,base=16,layout=2006,prec=10,sep=;,true=Y"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `csv tag option "sep" only supported for slice fields (field name: "Name")`, err.Error())
+	})
+	t.Run("Bad tag default", func(t *testing.T) {
+		type testStruct struct {
+			Age int `csv:"Age,default=abc"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `invalid csv tag option default="abc" - cannot convert value "abc" to int (field name: "Age")`, err.Error())
+		type enumStruct struct {
+			Colour string `csv:"Colour,enum=red|blue,default=green"`
+		}
+		_, err = NewMapper[enumStruct]()
+		require.Error(t, err)
+		require.Equal(t, `invalid csv tag option default="green" - value "green" is not one of "red", "blue" (field name: "Colour")`, err.Error())
+	})
+	t.Run("Bad tag options", func(t *testing.T) {
+		testCases := []struct {
+			tag       string
+			expectErr string
+		}{
+			{
+				tag:       "foo,layout",
+				expectErr: "csv tag option \"layout\" requires a value",
+			},
+			{
+				tag:       "foo,trim=yes",
+				expectErr: "csv tag option \"trim\" does not take a value",
+			},
+			{
+				tag:       "foo,trim,trim",
+				expectErr: "duplicate csv tag option \"trim\"",
+			},
+			{
+				tag:       "foo,default=x,required",
+				expectErr: "csv tag options \"default\" and \"required\" cannot be used together",
+			},
+			{
+				tag:       "foo,optional,required",
+				expectErr: "csv tag options \"optional\" and \"required\" cannot be used together",
+			},
+			{
+				tag:       "foo,layout=",
+				expectErr: "csv tag option \"layout\" cannot be empty",
+			},
+			{
+				tag:       "[line],trim",
+				expectErr: "csv tag options not supported with \"[line]\"",
+			},
+			{
+				tag:       "-,trim",
+				expectErr: "csv tag options not supported with \"-\"",
+			},
+			{
+				tag:       "'foo,trim",
+				expectErr: "unterminated quote in csv tag",
+			},
+			{
+				tag:       "'foo'bar,trim",
+				expectErr: "unexpected characters after quote in csv tag",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.tag, func(t *testing.T) {
+				_, err := parseFieldTag(tc.tag)
+				require.Error(t, err)
+				require.Equal(t, tc.expectErr, err.Error())
+			})
+		}
+		type testStruct struct {
+			Foo string `csv:"foo,default=x,required"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "(field name: \"Foo\")")
+	})
+	t.Run("Bad header regex", func(t *testing.T) {
+		type testStruct struct {
+			Foo []string `csv:"~^Q(\\d"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "invalid csv header regex \"~^Q(\\\\d\" (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Bad header regex map key", func(t *testing.T) {
+		type testStruct struct {
+			Foo map[int]string `csv:"~^Q"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "struct field unsupported map key type: int", err.Error())
+	})
+	t.Run("Bad index range", func(t *testing.T) {
+		type testStruct struct {
+			Foo []string `csv:"[5:2]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "invalid csv field index range [5:2] (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Bad index range type", func(t *testing.T) {
+		type testStruct struct {
+			Foo string `csv:"[1:2]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "field with csv index range [1:2] expected to be slice or map (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Overlapping index ranges", func(t *testing.T) {
+		type testStruct struct {
+			Foo []string `csv:"[1:3]"`
+			Bar []string `csv:"[3:]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "field with csv index range [3:] overlaps already mapped csv fields (field name: \"Bar\")", err.Error())
+	})
+	t.Run("Index range overlaps index", func(t *testing.T) {
+		type testStruct struct {
+			Foo string   `csv:"[2]"`
+			Bar []string `csv:"[1:3]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "field with csv index range [1:3] overlaps already mapped csv fields (field name: \"Bar\")", err.Error())
+	})
+	t.Run("Index within index range", func(t *testing.T) {
+		type testStruct struct {
+			Foo []string `csv:"[1:3]"`
+			Bar string   `csv:"[2]"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "field with csv index 2 already mapped  (field name: \"Bar\")", err.Error())
+	})
+	t.Run("Implied index within open-ended index range", func(t *testing.T) {
+		type testStruct struct {
+			Foo  string
+			Rest []string `csv:"[2:]"`
+			Bar  string
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "field with csv index 2 already mapped  (field name: \"Bar\")", err.Error())
+	})
+	t.Run("Bad droptrailing", func(t *testing.T) {
+		type testStruct struct {
+			Foo []string `csv:"Foo,droptrailing"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, "csv tag option \"droptrailing\" only supported for fields mapped to multiple csv fields (field name: \"Foo\")", err.Error())
+	})
+	t.Run("Bad tag time zone", func(t *testing.T) {
+		type testStruct struct {
+			Foo time.Time `csv:"foo,tz=Not/A_Zone"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid csv tag option tz=\"Not/A_Zone\"")
+	})
+	t.Run("Bad tag number format", func(t *testing.T) {
+		type testStruct struct {
+			Foo float64 `csv:"foo,grouping=."`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `number format grouping separator "." cannot be the same as the decimal separator (field name: "Foo")`, err.Error())
+		type otherStruct struct {
+			Foo float64 `csv:"foo,decimal=."`
+		}
+		_, err = NewMapper[otherStruct](NumberFormat{DecimalSeparator: ",", GroupingSeparator: "."})
+		require.Error(t, err)
+		require.Equal(t, `number format grouping separator "." cannot be the same as the decimal separator (field name: "Foo")`, err.Error())
+	})
 	t.Run("Duplicate field name", func(t *testing.T) {
 		type testStruct struct {
 			Foo string `csv:"Foo"`
@@ -520,3 +856,58 @@ func TestMapper_Adapt(t *testing.T) {
 		require.Contains(t, err.Error(), "struct field unsupported type:")
 	})
 }
+
+func TestMapper_Adapt_Overlaps(t *testing.T) {
+	type testStruct struct {
+		Name string   `csv:"[1]"`
+		Vals []string `csv:"[2:4]"`
+		X    []string `csv:"-"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	_, err = m.Adapt(false, OverrideMappings{{FieldName: "X", CsvFieldIndex: 3}})
+	require.Error(t, err)
+	require.Equal(t, "field with csv index 3 already mapped  (field name: \"X\")", err.Error())
+	_, err = m.Adapt(false, OverrideMappings{{FieldName: "Vals", CsvFieldName: "[1:3]"}})
+	require.Error(t, err)
+	require.Equal(t, "field with csv index range [1:3] overlaps already mapped csv fields (field name: \"Vals\")", err.Error())
+	_, err = m.Adapt(false, OverrideMappings{{FieldName: "X", CsvFieldName: "[4:]"}})
+	require.Error(t, err)
+	require.Equal(t, "field with csv index range [4:] overlaps already mapped csv fields (field name: \"X\")", err.Error())
+
+	am, err := m.Adapt(false, OverrideMappings{{FieldName: "Vals", CsvFieldName: "[2:3]"}, {FieldName: "X", CsvFieldIndex: 4}})
+	require.NoError(t, err)
+	require.Equal(t, OverrideMappings{
+		{FieldName: "Name", CsvFieldIndex: 1},
+		{FieldName: "Vals", CsvFieldName: "[2:3]"},
+		{FieldName: "X", CsvFieldIndex: 4},
+	}, am.Mappings())
+}
+
+func TestMapper_Adapt_Options(t *testing.T) {
+	type testStruct struct {
+		Foo time.Time
+		Bar time.Time `csv:"bar,layout=2006-01-02"`
+	}
+	m, err := NewMapper[testStruct](DefaultEmptyValues(true))
+	require.NoError(t, err)
+	m2, err := m.Adapt(false, nil, DefaultTimeLayout("02/01/2006"))
+	require.NoError(t, err)
+	rm2 := m2.(*mapper[testStruct])
+	require.True(t, rm2.defaultEmptyValues)
+	require.Equal(t, "02/01/2006", rm2.timeLayout)
+
+	const data = `Foo,bar
+02/01/2025,2025-01-03
+,`
+	recs, err := m2.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), recs[0].Foo)
+	require.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), recs[0].Bar)
+	require.True(t, recs[1].Foo.IsZero())
+
+	_, err = m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.Error(t, err)
+}
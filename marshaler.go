@@ -0,0 +1,20 @@
+package csvamp
+
+// CsvMarshaler is the interface implemented by an object that can
+// marshal itself into a string CSV field representation.
+//
+// This is effectively the same as encoding.TextMarshaler, but is provided
+// with the record being written (the fields preceding this field)
+type CsvMarshaler interface {
+	MarshalCSV(record []string) (string, error)
+}
+
+// CsvQuotedMarshaler is the interface implemented by an object that can
+// marshal itself into a string CSV field representation.
+//
+// CsvQuotedMarshaler is similar to CsvMarshaler, except that it also returns
+// whether the field should be quoted - true forces the field to be quoted, false
+// suppresses quoting (unless the value requires quoting)
+type CsvQuotedMarshaler interface {
+	MarshalQuotedCSV(record []string) (val string, quoted bool, err error)
+}
@@ -0,0 +1,155 @@
+package csvamp
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	decimalPoint  = "."
+	percentSuffix = "%"
+)
+
+// isZero determines whether the number format is the default (i.e. numbers are read and written as per strconv)
+func (nf *NumberFormat) isZero() bool {
+	return nf == nil || ((nf.DecimalSeparator == "" || nf.DecimalSeparator == decimalPoint) && nf.GroupingSeparator == "" &&
+		len(nf.CurrencySymbols) == 0 && !nf.ParenthesesNegative && !nf.Percent)
+}
+
+// validate checks that the grouping separator is not the decimal separator (which, if empty, is ".") - otherwise decimal values would be silently misread (e.g. "1.5" as 15)
+func (nf *NumberFormat) validate() error {
+	ds := nf.DecimalSeparator
+	if ds == "" {
+		ds = decimalPoint
+	}
+	if nf.GroupingSeparator == ds {
+		return fmt.Errorf("number format grouping separator %q cannot be the same as the decimal separator", nf.GroupingSeparator)
+	}
+	return nil
+}
+
+// parse converts a formatted number value to a value that can be parsed by strconv - returning whether the value had a percent suffix
+func (nf *NumberFormat) parse(val string) (string, bool) {
+	s := strings.TrimSpace(val)
+	neg := false
+	if nf.ParenthesesNegative && len(s) > 1 && strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s, neg = strings.TrimSpace(s[1:len(s)-1]), true
+	}
+	percent := false
+	if nf.Percent && strings.HasSuffix(s, percentSuffix) {
+		s, percent = strings.TrimSpace(s[:len(s)-len(percentSuffix)]), true
+	}
+	for _, cs := range nf.CurrencySymbols {
+		if cs != "" {
+			s = strings.ReplaceAll(s, cs, "")
+		}
+	}
+	s = strings.TrimSpace(s)
+	if nf.GroupingSeparator != "" {
+		s = strings.ReplaceAll(s, nf.GroupingSeparator, "")
+	}
+	if nf.DecimalSeparator != "" && nf.DecimalSeparator != decimalPoint {
+		s = strings.Replace(s, nf.DecimalSeparator, decimalPoint, 1)
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s, percent
+}
+
+// parseInteger converts an int or uint value to a value that can be parsed by strconv (a percent suffix is ignored)
+//
+// if a base is specified, underscore digit separators and any base prefix are also stripped
+func parseInteger(val string, base *int, nf *NumberFormat) string {
+	if nf != nil {
+		val, _ = nf.parse(val)
+	}
+	if base != nil {
+		val = baseDigits(val, *base)
+	}
+	return val
+}
+
+// baseOf returns the base for parsing (and formatting) integers - 10 if no base is specified
+func baseOf(base *int) int {
+	if base == nil {
+		return 10
+	}
+	return *base
+}
+
+// parseFloat converts a float value to a value that can be parsed by strconv (a percent suffix divides the value by 100)
+func parseFloat(val string, nf *NumberFormat) string {
+	if nf != nil {
+		var percent bool
+		if val, percent = nf.parse(val); percent {
+			// exponent notation avoids float rounding errors (i.e. vs dividing the parsed value by 100)...
+			val += "e-2"
+		}
+	}
+	return val
+}
+
+// basePrefixes are the Go integer literal prefixes for each base
+var basePrefixes = map[int]string{
+	2:  "0b",
+	8:  "0o",
+	16: "0x",
+}
+
+// baseDigits strips underscore digit separators and any base prefix (e.g. "0x" for base 16) from an integer value - so that it can be parsed by strconv
+//
+// base 0 values are left as is - strconv handles prefixes and underscores for base 0
+func baseDigits(val string, base int) string {
+	if base == 0 {
+		return val
+	}
+	s := strings.ReplaceAll(val, "_", "")
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if prefix, ok := basePrefixes[base]; ok && len(s) > len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		s = s[len(prefix):]
+	}
+	return sign + s
+}
+
+// formatInteger converts an int or uint value (as formatted by strconv) to the number format
+func (nf *NumberFormat) formatInteger(s string) string {
+	if nf.Percent {
+		return s + percentSuffix
+	}
+	return s
+}
+
+// formatFloat converts a float value (as formatted by strconv) to the number format
+func (nf *NumberFormat) formatFloat(s string) string {
+	if nf.Percent {
+		s = shiftDecimalPoint(s, 2) + percentSuffix
+	}
+	if nf.DecimalSeparator != "" && nf.DecimalSeparator != decimalPoint {
+		s = strings.Replace(s, decimalPoint, nf.DecimalSeparator, 1)
+	}
+	return s
+}
+
+// shiftDecimalPoint shifts the decimal point of a (strconv formatted, non-exponent) number n places to the right - i.e. multiplies by 10^n without float rounding errors
+func shiftDecimalPoint(s string, n int) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, decimalPoint)
+	for len(fracPart) < n {
+		fracPart += "0"
+	}
+	intPart, fracPart = strings.TrimLeft(intPart+fracPart[:n], "0"), fracPart[n:]
+	if intPart == "" {
+		intPart = "0"
+	}
+	if fracPart != "" {
+		return sign + intPart + decimalPoint + fracPart
+	}
+	return sign + intPart
+}
@@ -0,0 +1,114 @@
+package csvamp
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNumberFormat_Parse(t *testing.T) {
+	testCases := []struct {
+		nf            NumberFormat
+		value         string
+		expect        string
+		expectPercent bool
+	}{
+		{
+			value:  "1234.56",
+			expect: "1234.56",
+		},
+		{
+			nf:     NumberFormat{DecimalSeparator: ",", GroupingSeparator: "."},
+			value:  "1.234,56",
+			expect: "1234.56",
+		},
+		{
+			nf:     NumberFormat{GroupingSeparator: ",", CurrencySymbols: []string{"$"}},
+			value:  "$1,200",
+			expect: "1200",
+		},
+		{
+			nf:     NumberFormat{GroupingSeparator: ",", CurrencySymbols: []string{"$"}},
+			value:  "-$1,200",
+			expect: "-1200",
+		},
+		{
+			nf:     NumberFormat{DecimalSeparator: ",", GroupingSeparator: " ", CurrencySymbols: []string{"€", "EUR"}},
+			value:  " 1 234,5 € ",
+			expect: "1234.5",
+		},
+		{
+			nf:     NumberFormat{ParenthesesNegative: true, CurrencySymbols: []string{"$"}},
+			value:  "($42)",
+			expect: "-42",
+		},
+		{
+			nf:     NumberFormat{ParenthesesNegative: true},
+			value:  "(",
+			expect: "(",
+		},
+		{
+			nf:            NumberFormat{Percent: true, DecimalSeparator: ","},
+			value:         "12,5%",
+			expect:        "12.5",
+			expectPercent: true,
+		},
+		{
+			nf:     NumberFormat{Percent: true},
+			value:  "12.5",
+			expect: "12.5",
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			s, percent := tc.nf.parse(tc.value)
+			require.Equal(t, tc.expect, s)
+			require.Equal(t, tc.expectPercent, percent)
+		})
+	}
+}
+
+func TestShiftDecimalPoint(t *testing.T) {
+	testCases := []struct {
+		value  string
+		expect string
+	}{
+		{value: "0", expect: "0"},
+		{value: "1", expect: "100"},
+		{value: "0.07", expect: "7"},
+		{value: "0.125", expect: "12.5"},
+		{value: "-0.5", expect: "-50"},
+		{value: "0.001", expect: "0.1"},
+		{value: "12.3456", expect: "1234.56"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			require.Equal(t, tc.expect, shiftDecimalPoint(tc.value, 2))
+		})
+	}
+}
+
+func TestBaseDigits(t *testing.T) {
+	testCases := []struct {
+		value  string
+		base   int
+		expect string
+	}{
+		{value: "0x1F", base: 0, expect: "0x1F"},
+		{value: "1_000", base: 0, expect: "1_000"},
+		{value: "0x1F", base: 16, expect: "1F"},
+		{value: "0X1f", base: 16, expect: "1f"},
+		{value: "-0xff_ff", base: 16, expect: "-ffff"},
+		{value: "ff", base: 16, expect: "ff"},
+		{value: "0x", base: 16, expect: "0x"},
+		{value: "0o17", base: 8, expect: "17"},
+		{value: "0b1010_1010", base: 2, expect: "10101010"},
+		{value: "1_000_000", base: 10, expect: "1000000"},
+		{value: "0x1F", base: 36, expect: "0x1F"},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			require.Equal(t, tc.expect, baseDigits(tc.value, tc.base))
+		})
+	}
+}
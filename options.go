@@ -7,9 +7,130 @@ package csvamp
 // By default, if a struct field references (via tag) an unknown CSV field (header) name - it will error when reading
 type IgnoreUnknownFieldNames bool
 
+// DisallowUnknownColumns is an option that can be passed to NewMapper / MustNewMapper
+//
+// if set to true, reading errors (when the CSV headers are read) if the CSV contains headers that are not mapped to any struct field - the error lists all the unknown headers
+//
+// By default, CSV fields that are not mapped to any struct field are ignored
+type DisallowUnknownColumns bool
+
+// DisallowDuplicateHeaders is an option that can be passed to NewMapper / MustNewMapper
+//
+// if set to true, reading errors (when the CSV headers are read) if the CSV contains any duplicate headers
+//
+// By default, duplicate headers are not errors - a header name referenced by a struct field matches the last occurrence of that header, and a specific occurrence
+// can be referenced using the "#n" suffix (e.g. `csv:"Amount#2"` references the second "Amount" header)
+type DisallowDuplicateHeaders bool
+
 // DefaultEmptyValues is an option that can be passed to NewMapper / MustNewMapper
 //
 // if set to true, when reading, empty fields in the CSV are treated as zero values for types bool, int, uint and float
 //
 // By default, reading empty CSV fields into bool, int, uint & float will cause an error
 type DefaultEmptyValues bool
+
+// DefaultTimeLayout is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the default layout used for reading (and writing) time.Time fields - the layout can also be set per field using the csv tag option "layout" (e.g. `csv:"Created,layout=2006-01-02"`)
+//
+// By default, time.RFC3339 is used
+type DefaultTimeLayout string
+
+// TimeZone is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the time zone (location name, e.g. "Europe/London") used when reading (and writing) time.Time fields - the time zone can also be set per field using the csv tag option "tz" (e.g. `csv:"Created,tz=America/New_York"`)
+//
+// By default, UTC is used
+type TimeZone string
+
+// DefaultSliceSeparator is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the default separator used to split (and join) the values of slice fields - the separator can also be set per field using the csv tag option "sep" (e.g. `csv:"Tags,sep=;"`)
+//
+// By default, "," is used
+type DefaultSliceSeparator string
+
+// HeaderNormalization is an option that can be passed to NewMapper / MustNewMapper
+//
+// it determines the normalizations applied to both the CSV header names and the header names referenced by struct fields (via tag) - so that
+// headers can be matched despite differences in case, whitespace etc.
+//
+// Normalizations can be combined, e.g.
+//
+//	csvamp.NewMapper[MyStruct](csvamp.HeaderFoldCase | csvamp.HeaderTrimSpace)
+//
+// By default, no normalization is applied and headers must match exactly
+type HeaderNormalization int
+
+const (
+	// HeaderTrimSpace trims leading and trailing whitespace (and collapses runs of whitespace to a single space)
+	HeaderTrimSpace HeaderNormalization = 1 << iota
+	// HeaderFoldCase folds to lower case
+	HeaderFoldCase
+	// HeaderStripBOM strips a leading byte order mark (BOM)
+	HeaderStripBOM
+	// HeaderCollapsePunctuation collapses runs of punctuation, underscores and whitespace to a single space (and trims leading and trailing)
+	//
+	// e.g. "first_name", "First-Name" and "first. name" all become "first name" (when used with HeaderFoldCase)
+	HeaderCollapsePunctuation
+	// HeaderNormalizeAll applies all the header normalizations
+	HeaderNormalizeAll = HeaderTrimSpace | HeaderFoldCase | HeaderStripBOM | HeaderCollapsePunctuation
+)
+
+// HeaderNormalizer is an option that can be passed to NewMapper / MustNewMapper
+//
+// it is a function used to normalize both the CSV header names and the header names referenced by struct fields (via tag)
+//
+// If used in conjunction with the HeaderNormalization option, the HeaderNormalizer is applied after the HeaderNormalization
+type HeaderNormalizer func(string) string
+
+// NumberFormat is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the number format used when reading int, uint and float fields (e.g. for locale specific numbers such as "1.234,56", "$1,200" or "(42)") - the
+// number format can also be set per field using the csv tag options "decimal", "grouping", "currency", "parens" and "percent"
+// (e.g. `csv:"Amount,decimal=',',grouping=.,currency=€"`)
+//
+// when writing float fields, only the decimal separator and percent suffix are used
+//
+// By default, numbers are read and written using strconv (i.e. with no grouping separators, currency symbols etc.)
+type NumberFormat struct {
+	// DecimalSeparator is the decimal separator (e.g. ",") - if empty, "." is used
+	DecimalSeparator string
+	// GroupingSeparator is the (thousands) grouping separator (e.g. "," or ".") - grouping separators are removed when reading
+	//
+	// the grouping separator cannot be the same as the decimal separator (so "." can only be used when the decimal separator is set, e.g. to ",")
+	GroupingSeparator string
+	// CurrencySymbols are the currency symbols (e.g. "$", "€") that are removed when reading
+	CurrencySymbols []string
+	// ParenthesesNegative determines whether values in parentheses are negative (e.g. "(42)" is read as -42)
+	ParenthesesNegative bool
+	// Percent determines whether values have a percent suffix (e.g. "12.5%") - float values are divided by 100 when reading (and multiplied by 100 when writing)
+	Percent bool
+}
+
+// BoolValues is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the (case-insensitive) tokens read as true and false for bool fields (e.g. "Y"/"N", "yes"/"no", "on"/"off" or "X"/"") - the tokens can also be
+// set per field using the csv tag options "true" and "false" - where multiple tokens are separated by "|" (e.g. `csv:"Active,true=Y|yes,false=N|no"`)
+//
+// An empty token (e.g. `csv:"Active,true=X,false=N|"`) means that an empty CSV field is read as false (and also applies to *bool fields)
+//
+// when writing, the first true or false token is used
+//
+// By default (or when either True or False is not set), the strconv.ParseBool tokens are used
+type BoolValues struct {
+	// True are the tokens read as true
+	True []string
+	// False are the tokens read as false
+	False []string
+}
+
+// NullValues is an option that can be passed to NewMapper / MustNewMapper
+//
+// it sets the (case-sensitive) tokens that denote a null value when reading (e.g. "NULL", "N/A", `\N` or "-") - the tokens can also be set per field
+// using the csv tag option "null" - where multiple tokens are separated by "|" (e.g. `csv:"Amount,null=NULL|N/A"`)
+//
+// a null token is read as an empty value - so pointer fields are set to nil and, when used with DefaultEmptyValues, non-pointer fields are set to their zero value
+//
+// By default, there are no null tokens (only empty values are read as nil for pointer fields)
+type NullValues []string
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/go-andiamo/csvamp/csv"
 	"io"
+	"reflect"
 )
 
 // ReaderContext is the interface used to actually read structs from CSV
@@ -27,6 +28,12 @@ type ReaderContext[T any] interface {
 	//
 	// Sometimes your csv may not have headers, or you may have already read (and normalised) them
 	SupplyHeaders(headers []string) ReaderContext[T]
+	// ValidateHeaders reads (if not already read or supplied) and validates the CSV headers against the struct field mappings
+	//
+	// If the headers are invalid, the returned error is a *HeadersError - listing all missing, duplicate and unexpected headers
+	//
+	// Headers are also automatically validated before the first CSV line is read
+	ValidateHeaders() error
 }
 
 // ErrorHandler is an interface that can be used with ReaderContext.WithErrorHandler
@@ -40,7 +47,10 @@ type readerContext[T any] struct {
 	mapper         *mapper[T]
 	postProcessor  func(row *T) error
 	csvHeadersRead bool
-	csvHeaders     map[string]int
+	csvHeaders     map[string]int   // resolved csv header index for each header name referenced by struct fields
+	csvColumns     map[string][]int // resolved csv header indices for each header regex referenced by multiple csv field struct fields
+	headers        []string
+	consumed       map[int]bool // csv field indices consumed by header names referenced by struct fields
 	csvHeadersErr  error
 	errorHandler   ErrorHandler
 }
@@ -48,6 +58,11 @@ type readerContext[T any] struct {
 func (rc *readerContext[T]) Read() (t T, err error) {
 	var record []string
 	if record, err = rc.reader.Read(); err == nil {
+		if rc.requiresHeaders() {
+			if err = rc.checkCsvHeaders(); err != nil {
+				return t, err
+			}
+		}
 		if rc.mapper.lineMapper != nil {
 			rc.mapper.lineMapper(&t, rc.reader)
 		}
@@ -57,28 +72,17 @@ func (rc *readerContext[T]) Read() (t T, err error) {
 		if rc.mapper.rawDataMapper != nil {
 			rc.mapper.rawDataMapper(&t, rc.reader.RawRecord())
 		}
-		for i, v := range record {
-			if fn, ok := rc.mapper.csvFieldIndices[i+1]; ok {
-				if err = fn(&t, v, rc.reader.FieldQuoted(i), rc.mapper.defaultEmptyValues, record); err != nil {
-					return t, err
-				}
+		rc.allocNestedStructs(&t, record)
+		// fields are set in struct field order...
+		for i := range rc.mapper.fieldSetters {
+			if err = rc.setField(&t, &rc.mapper.fieldSetters[i], record); err != nil {
+				return t, err
 			}
 		}
-		if len(rc.mapper.csvFieldNames) > 0 {
-			if err = rc.checkCsvHeaders(); err == nil {
-				l := len(record)
-				for name, fn := range rc.mapper.csvFieldNames {
-					if idx, ok := rc.csvHeaders[name]; ok && idx >= 0 && idx < l {
-						if err = fn(&t, record[idx], rc.reader.FieldQuoted(idx), rc.mapper.defaultEmptyValues, record); err != nil {
-							return t, err
-						}
-					} else if !rc.mapper.ignoreUnknownFieldNames {
-						return t, fmt.Errorf("csv header %q not present", name)
-					}
-				}
-			}
+		if rc.mapper.extraMapper != nil {
+			rc.mapper.extraMapper(&t, rc.extraColumns(record))
 		}
-		if rc.postProcessor != nil && err == nil {
+		if rc.postProcessor != nil {
 			err = rc.postProcessor(&t)
 		}
 	}
@@ -128,22 +132,24 @@ func (rc *readerContext[T]) WithErrorHandler(eh ErrorHandler) ReaderContext[T] {
 
 func (rc *readerContext[T]) SupplyHeaders(headers []string) ReaderContext[T] {
 	rc.csvHeadersRead = true
-	rc.csvHeadersErr = nil
-	rc.csvHeaders = make(map[string]int, len(headers))
-	for i, h := range headers {
-		rc.csvHeaders[h] = i
-	}
+	rc.csvHeadersErr = rc.resolveHeaders(headers)
 	return rc
 }
 
+func (rc *readerContext[T]) ValidateHeaders() error {
+	if !rc.requiresHeaders() {
+		return nil
+	}
+	return rc.checkCsvHeaders()
+}
+
 func (rc *readerContext[T]) checkCsvHeaders() error {
 	if !rc.csvHeadersRead {
 		rc.csvHeadersRead = true
-		if hdrs, has := rc.reader.Header(); has {
-			rc.csvHeaders = make(map[string]int, len(hdrs))
-			for i, h := range hdrs {
-				rc.csvHeaders[h] = i
-			}
+		if hdrs, err := rc.reader.ReadHeader(); err != nil && err != io.EOF {
+			rc.csvHeadersErr = err
+		} else if _, has := rc.reader.Header(); has && err == nil {
+			rc.csvHeadersErr = rc.resolveHeaders(hdrs)
 		} else {
 			rc.csvHeadersErr = errors.New("csv headers not present")
 		}
@@ -151,6 +157,116 @@ func (rc *readerContext[T]) checkCsvHeaders() error {
 	return rc.csvHeadersErr
 }
 
+// setField sets the struct field from its csv field(s)
+func (rc *readerContext[T]) setField(t *T, fs *fieldSetter[T], record []string) error {
+	defEmpties := rc.mapper.defaultEmptyValues
+	switch {
+	case fs.fr != nil:
+		return rc.fieldError(fs.fr.setter(t, fs.fr.columns(record, rc.headers, rc.reader.FieldQuoted), defEmpties, record), -1, record)
+	case fs.columns != nil:
+		if columns, ok := rc.headerColumns(fs.name, record); ok {
+			return rc.fieldError(fs.columns(t, columns, defEmpties, record), -1, record)
+		}
+		return nil
+	}
+	if idx, ok := rc.csvIndex(fs); !ok {
+		return rc.setAbsentField(t, fs, record)
+	} else if idx < len(record) {
+		return rc.fieldError(fs.setter(t, record[idx], rc.reader.FieldQuoted(idx), defEmpties, record), idx, record)
+	} else if fs.index == 0 && !rc.mapper.ignoreUnknownFieldNames {
+		// the header is present - but the record is short...
+		return fmt.Errorf("csv header %q field not present in record", fs.name)
+	}
+	return rc.setAbsentField(t, fs, record)
+}
+
+// setAbsentField handles a struct field whose csv field is absent (i.e. a short record, or an optional or ignored header not present)
+//
+// the field is only set if it is required (which errors) or has a default value
+func (rc *readerContext[T]) setAbsentField(t *T, fs *fieldSetter[T], record []string) error {
+	if !fs.opts.required && fs.opts.defaultValue == nil {
+		return nil
+	}
+	err := rc.fieldError(fs.setter(t, "", false, rc.mapper.defaultEmptyValues, record), fs.index-1, record)
+	var fe *FieldError
+	if fs.index == 0 && errors.As(err, &fe) {
+		fe.Header = fs.name
+	}
+	return err
+}
+
+// csvIndex returns the (0 based) csv field index for a struct field mapped to a single csv field (by index or header name)
+//
+// returns false if the header is not present - headers not present have already been reported (unless ignored or optional)
+func (rc *readerContext[T]) csvIndex(fs *fieldSetter[T]) (int, bool) {
+	if fs.index > 0 {
+		return fs.index - 1, true
+	}
+	// negative index is an optional header not present...
+	idx, ok := rc.csvHeaders[fs.name]
+	return idx, ok && idx >= 0
+}
+
+// allocNestedStructs allocates the pointers to nested (or embedded) structs that have any non-empty mapped csv field
+//
+// allocation is decided before any field is set - so that all the fields of an allocated nested struct are set (including required and default values)
+func (rc *readerContext[T]) allocNestedStructs(t *T, record []string) {
+	v := reflect.ValueOf(t).Elem()
+	for i := range rc.mapper.fieldSetters {
+		if fs := &rc.mapper.fieldSetters[i]; fs.lazy && rc.anyNonEmpty(fs, record) {
+			fieldByPath(v, fs.path, true)
+		}
+	}
+}
+
+// anyNonEmpty determines whether any of the csv fields mapped to the struct field is non-empty (and not a null token)
+func (rc *readerContext[T]) anyNonEmpty(fs *fieldSetter[T], record []string) bool {
+	switch {
+	case fs.fr != nil:
+		return anyNonEmpty(fs.fr.columns(record, rc.headers, rc.reader.FieldQuoted), fs.opts)
+	case fs.columns != nil:
+		columns, _ := rc.headerColumns(fs.name, record)
+		return anyNonEmpty(columns, fs.opts)
+	}
+	idx, ok := rc.csvIndex(fs)
+	return ok && idx < len(record) && !(csvColumn{value: record[idx]}).isEmpty(fs.opts)
+}
+
+// headerColumns returns the csv fields (and their headers) matched by a header regex or header pattern
+func (rc *readerContext[T]) headerColumns(name string, record []string) ([]csvColumn, bool) {
+	idxs, ok := rc.csvColumns[name]
+	if !ok {
+		return nil, false
+	}
+	columns := make([]csvColumn, 0, len(idxs))
+	for _, idx := range idxs {
+		if idx < len(record) {
+			columns = append(columns, csvColumn{index: idx, header: rc.headers[idx], value: record[idx], quoted: rc.reader.FieldQuoted(idx)})
+		}
+	}
+	return columns, true
+}
+
+// fieldError completes the csv field details (line, column, index and header) of a *FieldError - a negative index means the error already has the csv field index
+func (rc *readerContext[T]) fieldError(err error, idx int, record []string) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		if idx >= 0 {
+			fe.Index = idx + 1
+		}
+		if fe.Index > 0 && fe.Index <= len(record) {
+			fe.Line, fe.Column = rc.reader.FieldPos(fe.Index - 1)
+		} else {
+			// the csv field is absent...
+			fe.Line = rc.reader.CurrentLine()
+		}
+		if fe.Header == "" && fe.Index > 0 && fe.Index <= len(rc.headers) {
+			fe.Header = rc.headers[fe.Index-1]
+		}
+	}
+	return err
+}
+
 func (rc *readerContext[T]) handleError(err error) error {
 	if err == nil {
 		return nil
@@ -176,3 +292,34 @@ func (e *ReaderError) Error() string {
 func (e *ReaderError) Unwrap() error {
 	return e.Err
 }
+
+// FieldError is the error returned when reading a CSV field value into a struct field fails (e.g. the value cannot be converted to the struct field type)
+//
+// FieldError is wrapped by ReaderError (when using ReaderContext.ReadAll / ReaderContext.Iterate) - use errors.As to obtain it
+type FieldError struct {
+	// Line is the line number of the CSV field (which may differ from the record start line for multi-line quoted fields)
+	Line int
+	// Column is the (1 based) column of the CSV field within the line - counted in bytes, not runes (zero if the CSV field is absent)
+	Column int
+	// Index is the (1 based) CSV field index (zero if the CSV field is absent and the struct field is mapped by header name)
+	Index int
+	// Header is the CSV header of the CSV field (empty if the CSV has no headers) - or the header name referenced by the struct field, if that header is not present
+	Header string
+	// Field is the struct field path (e.g. "Address.Street" for nested struct fields)
+	Field string
+	// Value is the raw CSV field value
+	Value string
+	// Type is the target type - the struct field type (or the element type for slice and map fields mapped to multiple CSV fields)
+	Type reflect.Type
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the underlying error message (the details are available as FieldError fields)
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -2,10 +2,14 @@ package csvamp
 
 import (
 	"errors"
+	"fmt"
 	"github.com/go-andiamo/csvamp/csv"
 	"github.com/stretchr/testify/require"
+	"math/big"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestReaderContext_Read(t *testing.T) {
@@ -253,6 +257,95 @@ Ddd,Eee,Fff`
 	require.Equal(t, 3, eh.lines[1])
 }
 
+func TestReaderContext_ReadAll_FieldErrors(t *testing.T) {
+	type address struct {
+		Zip int `csv:"Zip"`
+	}
+	type testStruct struct {
+		Name    string `csv:"Name"`
+		Age     *int   `csv:"Age"`
+		Address *address
+		Scores  []int   `csv:"Score*"`
+		Rest    []uint8 `csv:"[6:]"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Name,Age,Zip,Score1,Score2,X,Y
+Bilbo,x,1,1,2,3,4
+"Frodo
+Baggins",1,2,3,4,5,6
+Sam,1,zip,1,2,3,4
+Merry,1,1,1,bad,3,4
+Pippin,1,1,1,2,3,256`
+	eh := &testErrorHandler{}
+	_, err = m.Reader(strings.NewReader(data), nil).WithErrorHandler(eh).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, eh.errs, 4)
+	fieldErrors := make([]*FieldError, 0, len(eh.errs))
+	for _, err := range eh.errs {
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		fieldErrors = append(fieldErrors, fe)
+	}
+	require.Equal(t, &FieldError{Line: 2, Column: 7, Index: 2, Header: "Age", Field: "Age", Value: "x", Type: reflect.TypeOf((*int)(nil)), Err: fieldErrors[0].Err}, fieldErrors[0])
+	require.Equal(t, `cannot convert value "x" to int`, fieldErrors[0].Error())
+	require.Equal(t, &FieldError{Line: 5, Column: 7, Index: 3, Header: "Zip", Field: "Address.Zip", Value: "zip", Type: reflect.TypeOf(0), Err: fieldErrors[1].Err}, fieldErrors[1])
+	require.Equal(t, &FieldError{Line: 6, Column: 13, Index: 5, Header: "Score2", Field: "Scores", Value: "bad", Type: reflect.TypeOf(0), Err: fieldErrors[2].Err}, fieldErrors[2])
+	require.Equal(t, &FieldError{Line: 7, Column: 18, Index: 7, Header: "Y", Field: "Rest", Value: "256", Type: reflect.TypeOf(uint8(0)), Err: fieldErrors[3].Err}, fieldErrors[3])
+	require.Equal(t, `value "256" overflows uint8`, fieldErrors[3].Error())
+
+	t.Run("Multi-line field", func(t *testing.T) {
+		type testStruct struct {
+			Name string `csv:"Name"`
+			Age  int    `csv:"Age"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader("Name,Age\n\"Frodo\nBaggins\",x"), nil).ReadAll()
+		require.Error(t, err)
+		var re *ReaderError
+		require.True(t, errors.As(err, &re))
+		require.Equal(t, 2, re.Line)
+		require.Equal(t, `line 2: cannot convert value "x" to int`, err.Error())
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, 3, fe.Line)
+		require.Equal(t, 10, fe.Column)
+		require.Equal(t, 2, fe.Index)
+		require.Equal(t, "Age", fe.Header)
+	})
+	t.Run("No headers", func(t *testing.T) {
+		type testStruct struct {
+			Name string `csv:"[1]"`
+			Age  int    `csv:"[2]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader("Bilbo,x"), nil, csv.NoHeader(true)).Read()
+		require.Error(t, err)
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, &FieldError{Line: 1, Column: 7, Index: 2, Field: "Age", Value: "x", Type: reflect.TypeOf(0), Err: fe.Err}, fe)
+	})
+	t.Run("Converter error", func(t *testing.T) {
+		type testStruct struct {
+			Name string `csv:"Name"`
+		}
+		fooey := errors.New("fooey")
+		m, err := NewMapper[testStruct](WithConverter[string](func(val string, quoted bool, record []string) (string, error) {
+			return "", fooey
+		}))
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader("Name\nBilbo"), nil).Read()
+		require.Error(t, err)
+		require.ErrorIs(t, err, fooey)
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, "Name", fe.Field)
+		require.Equal(t, "Bilbo", fe.Value)
+	})
+}
 func TestReaderContext_Iterate(t *testing.T) {
 	type testStruct struct {
 		Line    int      `csv:"[line]"`
@@ -432,6 +525,25 @@ Aaa,"Bbb"`
 		require.NoError(t, err)
 		require.Equal(t, "", result.Foo)
 	})
+	t.Run("Csv field not present - ignored", func(t *testing.T) {
+		type testStruct struct {
+			Foo string `csv:"Foo"`
+			Bar string `csv:"Bar"`
+		}
+		m, err := NewMapper[testStruct](IgnoreUnknownFieldNames(true))
+		require.NoError(t, err)
+
+		const data = "Foo,Bar\nx\n"
+		result, err := m.Reader(strings.NewReader(data), nil, csv.FieldsPerRecord(-1)).Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{Foo: "x"}, result)
+
+		am, err := m.Adapt(false, nil, IgnoreUnknownFieldNames(false))
+		require.NoError(t, err)
+		_, err = am.Reader(strings.NewReader(data), nil, csv.FieldsPerRecord(-1)).Read()
+		require.Error(t, err)
+		require.Equal(t, `csv header "Bar" field not present in record`, err.Error())
+	})
 	t.Run("No Headers", func(t *testing.T) {
 		type testStruct struct {
 			Foo string `csv:"Foo"`
@@ -476,6 +588,1174 @@ Ddd,Eee,Fff`
 	require.Equal(t, "Eee", result[1].Baz)
 }
 
+func TestReaderContext_Read_Times(t *testing.T) {
+	type testStruct struct {
+		Created  time.Time      `csv:"Created,layout=02/01/2006"`
+		Updated  *time.Time     `csv:"Updated,layout=2006-01-02 15:04,tz=America/New_York"`
+		Duration *time.Duration `csv:"Duration"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Created,Updated,Duration
+02/01/2025,2025-01-02 10:30,1h0m0s
+03/01/2025,,`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), recs[0].Created)
+	require.Equal(t, time.Date(2025, 1, 2, 10, 30, 0, 0, loc), *recs[0].Updated)
+	require.Equal(t, time.Hour, *recs[0].Duration)
+	require.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), recs[1].Created)
+	require.Nil(t, recs[1].Updated)
+	require.Nil(t, recs[1].Duration)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, data+"\n", buf.String())
+}
+
+func TestReaderContext_Read_TagOptions(t *testing.T) {
+	type testStruct struct {
+		Name  string  `csv:"Name,trim,required"`
+		Age   int     `csv:"Age,default=18"`
+		Notes *string `csv:"Notes,trim"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Name,Age,Notes
+  Bilbo  ,111,"  a hobbit "
+Frodo,,"   "
+"   ",33,`
+	r := m.Reader(strings.NewReader(data), nil)
+	row, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "Bilbo", row.Name)
+	require.Equal(t, 111, row.Age)
+	require.Equal(t, "a hobbit", *row.Notes)
+	row, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "Frodo", row.Name)
+	require.Equal(t, 18, row.Age)
+	require.NotNil(t, row.Notes)
+	require.Equal(t, "", *row.Notes)
+	_, err = r.Read()
+	require.Error(t, err)
+	require.Equal(t, "value required", err.Error())
+
+	t.Run("Absent csv fields", func(t *testing.T) {
+		type testStruct struct {
+			A string `csv:"[1]"`
+			B string `csv:"[2],required"`
+			C int    `csv:"[3],default=7"`
+			D int    `csv:"[4]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		r := m.Reader(strings.NewReader("a,b\na\n"), nil, csv.NoHeader(true), csv.FieldsPerRecord(-1))
+		row, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{A: "a", B: "b", C: 7}, row)
+		_, err = r.Read()
+		require.Error(t, err)
+		require.Equal(t, "value required", err.Error())
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, &FieldError{Line: 2, Index: 2, Field: "B", Type: reflect.TypeOf(""), Err: fe.Err}, fe)
+	})
+	t.Run("Absent headers", func(t *testing.T) {
+		type testStruct struct {
+			A string `csv:"a"`
+			B string `csv:"b,required"`
+			C int    `csv:"c,optional,default=7"`
+		}
+		m, err := NewMapper[testStruct](IgnoreUnknownFieldNames(true))
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("a,b\na,b"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{A: "a", B: "b", C: 7}, row)
+		_, err = m.Reader(strings.NewReader("a\na"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, "value required", err.Error())
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, &FieldError{Line: 2, Header: "b", Field: "B", Type: reflect.TypeOf(""), Err: fe.Err}, fe)
+	})
+}
+
+func TestReaderContext_Read_NormalizedHeaders(t *testing.T) {
+	type testStruct struct {
+		FirstName string `csv:"first_name"`
+		LastName  string `csv:"Last Name"`
+		Age       int    `csv:"AGE"`
+	}
+	const data = "\uFEFFFirst Name, last-name ,Age\nBilbo,Baggins,111"
+	t.Run("Not normalized", func(t *testing.T) {
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader(data), nil).Read()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not present")
+	})
+	t.Run("Normalized", func(t *testing.T) {
+		m, err := NewMapper[testStruct](HeaderNormalizeAll)
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, "Bilbo", row.FirstName)
+		require.Equal(t, "Baggins", row.LastName)
+		require.Equal(t, 111, row.Age)
+	})
+	t.Run("Normalizer", func(t *testing.T) {
+		m, err := NewMapper[testStruct](HeaderNormalizer(func(s string) string {
+			return strings.ToLower(strings.Trim(strings.NewReplacer("_", "", "-", "", " ", "").Replace(s), "\uFEFF"))
+		}))
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, "Bilbo", row.FirstName)
+		require.Equal(t, "Baggins", row.LastName)
+		require.Equal(t, 111, row.Age)
+	})
+	t.Run("Supplied headers", func(t *testing.T) {
+		m, err := NewMapper[testStruct](HeaderFoldCase | HeaderCollapsePunctuation)
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("Bilbo,Baggins,111"), nil, csv.NoHeader(true)).SupplyHeaders([]string{"FIRST NAME", "LAST NAME", "AGE"}).Read()
+		require.NoError(t, err)
+		require.Equal(t, "Bilbo", row.FirstName)
+		require.Equal(t, "Baggins", row.LastName)
+		require.Equal(t, 111, row.Age)
+	})
+}
+
+func TestReaderContext_Read_HeaderAliases(t *testing.T) {
+	type testStruct struct {
+		Name     string `csv:"Name"`
+		Postcode string `csv:"Postcode|Zip|Postal Code"`
+		Country  string `csv:"Country|Nation,optional"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	testCases := []struct {
+		data         string
+		expectCode   string
+		expectNation string
+		expectErr    string
+	}{
+		{
+			data:         "Name,Postcode,Country\nBilbo,BE1 1AA,Shire",
+			expectCode:   "BE1 1AA",
+			expectNation: "Shire",
+		},
+		{
+			data:       "Name,Zip\nBilbo,BE1 1AA",
+			expectCode: "BE1 1AA",
+		},
+		{
+			data:         "Postal Code,Nation,Name\nBE1 1AA,Shire,Bilbo",
+			expectCode:   "BE1 1AA",
+			expectNation: "Shire",
+		},
+		{
+			data:         "Name,Zip,Postcode,Nation\nBilbo,ZZ,BE1 1AA,Shire",
+			expectCode:   "BE1 1AA",
+			expectNation: "Shire",
+		},
+		{
+			data:      "Name,Country\nBilbo,Shire",
+			expectErr: "csv header \"Postcode|Zip|Postal Code\" not present",
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			row, err := m.Reader(strings.NewReader(tc.data), nil).Read()
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.expectErr, err.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "Bilbo", row.Name)
+				require.Equal(t, tc.expectCode, row.Postcode)
+				require.Equal(t, tc.expectNation, row.Country)
+			}
+		})
+	}
+	t.Run("Writes first alias", func(t *testing.T) {
+		var buf strings.Builder
+		err := m.Writer(&buf).WriteAll([]testStruct{{Name: "Bilbo", Postcode: "BE1 1AA", Country: "Shire"}})
+		require.NoError(t, err)
+		require.Equal(t, "Name,Postcode,Country\nBilbo,BE1 1AA,Shire\n", buf.String())
+	})
+	t.Run("Escaped separator", func(t *testing.T) {
+		type testStruct struct {
+			InOut string `csv:"In||Out|I/O"`
+			Pipe  string `csv:"||"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("|,In|Out\np,io"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{InOut: "io", Pipe: "p"}, row)
+		row, err = m.Reader(strings.NewReader("|,I/O\np,io"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{InOut: "io", Pipe: "p"}, row)
+
+		var buf strings.Builder
+		err = m.Writer(&buf).WriteAll([]testStruct{row})
+		require.NoError(t, err)
+		require.Equal(t, "In|Out,|\nio,p\n", buf.String())
+	})
+}
+
+func TestReaderContext_Read_FieldOrder(t *testing.T) {
+	type testStruct struct {
+		A int     `csv:"a"`
+		B []int   `csv:"b*"`
+		C int     `csv:"[4]"`
+		D []int   `csv:"[5:]"`
+		E float64 `csv:"e"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	testCases := []struct {
+		line      string
+		expectErr string
+	}{
+		{line: "x,x,x,x,x,x", expectErr: `cannot convert value "x" to int (field "A")`},
+		{line: "x,x,1,x,x,x", expectErr: `cannot convert value "x" to int (field "B")`},
+		{line: "x,1,1,x,x,x", expectErr: `cannot convert value "x" to int (field "C")`},
+		{line: "x,1,1,1,x,x", expectErr: `cannot convert value "x" to int (field "D")`},
+		{line: "x,1,1,1,1,1", expectErr: `cannot convert value "x" to float64 (field "E")`},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
+			// fields are set in struct field order - so the first bad value (in struct field order) is always reported...
+			for n := 0; n < 20; n++ {
+				_, err := m.Reader(strings.NewReader("e,b1,a,c,d1,d2\n"+tc.line), nil).Read()
+				require.Error(t, err)
+				var fe *FieldError
+				require.True(t, errors.As(err, &fe))
+				require.Equal(t, tc.expectErr, fmt.Sprintf("%s (field %q)", err.Error(), fe.Field))
+			}
+		})
+	}
+}
+
+func TestReaderContext_Read_HeaderRegex(t *testing.T) {
+	type testStruct struct {
+		Name       string             `csv:"Name"`
+		First      int                `csv:"~^Q\\d 2025 Revenue$"`
+		Revenues   []int              `csv:"~^Q\\d \\d{4} Revenue$"`
+		ByQuarter  map[string]float64 `csv:"'~^Q\\d{1,2} \\d{4} Revenue$'"`
+		Notes      []*string          `csv:"~^Note,optional"`
+		Unmatched  string             `csv:"~^Nothing,optional"`
+		Unmatcheds []string           `csv:"~^None,optional"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Name,Q1 2025 Revenue,Q2 2025 Revenue,Costs,Q3 2025 Revenue
+Acme,10,20,5,30`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, "Acme", row.Name)
+	require.Equal(t, 10, row.First)
+	require.Equal(t, []int{10, 20, 30}, row.Revenues)
+	require.Equal(t, map[string]float64{"Q1 2025 Revenue": 10, "Q2 2025 Revenue": 20, "Q3 2025 Revenue": 30}, row.ByQuarter)
+	require.Empty(t, row.Notes)
+	require.Equal(t, "", row.Unmatched)
+	require.Empty(t, row.Unmatcheds)
+
+	t.Run("Not present", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Name,Costs\nAcme,5"), nil).Read()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "csv headers \"~^Q")
+		var hdrsErr *HeadersError
+		require.True(t, errors.As(err, &hdrsErr))
+		require.Len(t, hdrsErr.Missing, 3)
+	})
+	t.Run("Bad value", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Name,Q1 2025 Revenue,Q2 2025 Revenue\nAcme,10,x"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, "cannot convert value \"x\" to int", err.Error())
+	})
+	t.Run("Normalized", func(t *testing.T) {
+		type testStruct struct {
+			Revenues []int `csv:"~^q\\d revenue$"`
+		}
+		m, err := NewMapper[testStruct](HeaderFoldCase | HeaderCollapsePunctuation)
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("Q1_Revenue,Q2-Revenue,Costs\n1,2,3"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, row.Revenues)
+	})
+	t.Run("Adapted", func(t *testing.T) {
+		am, err := m.Adapt(false, OverrideMappings{{FieldName: "Revenues", CsvFieldName: "~^Q[12] "}})
+		require.NoError(t, err)
+		row, err := am.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, []int{10, 20}, row.Revenues)
+		require.Len(t, row.ByQuarter, 3)
+	})
+	t.Run("Not writable", func(t *testing.T) {
+		var buf strings.Builder
+		err := m.Writer(&buf).WriteAll([]testStruct{row})
+		require.Error(t, err)
+		require.Equal(t, `field with csv header "~^Q\\d 2025 Revenue$" cannot be written - expected to be map (field name: "First")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_SpreadColumns(t *testing.T) {
+	type testStruct struct {
+		Name   string   `csv:"Name"`
+		Phones []string `csv:"Phone*,droptrailing"`
+		Scores []int    `csv:"[5:7],droptrailing"`
+		Rest   []string `csv:"[8:]"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Name,Phone1,Phone2,Phone3,S1,S2,S3,X,Y
+Bilbo,111,,333,1,2,,x,y
+Frodo,444,,,,,,x,
+Sam,,,,3,,,,`
+	r := m.Reader(strings.NewReader(data), nil)
+	row, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "Bilbo", row.Name)
+	require.Equal(t, []string{"111", "", "333"}, row.Phones)
+	require.Equal(t, []int{1, 2}, row.Scores)
+	require.Equal(t, []string{"x", "y"}, row.Rest)
+	row, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, []string{"444"}, row.Phones)
+	require.Empty(t, row.Scores)
+	require.Equal(t, []string{"x", ""}, row.Rest)
+	row, err = r.Read()
+	require.NoError(t, err)
+	require.Empty(t, row.Phones)
+	require.Equal(t, []int{3}, row.Scores)
+	require.Equal(t, []string{"", ""}, row.Rest)
+
+	t.Run("Normalized pattern", func(t *testing.T) {
+		type testStruct struct {
+			Phones map[string]string `csv:"phone *"`
+		}
+		m, err := NewMapper[testStruct](HeaderFoldCase | HeaderCollapsePunctuation)
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("Phone_Home,Name,PHONE-Work\n1,x,2"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"Phone_Home": "1", "PHONE-Work": "2"}, row.Phones)
+	})
+	t.Run("Not unknown", func(t *testing.T) {
+		am, err := m.Adapt(false, nil, DisallowUnknownColumns(true))
+		require.NoError(t, err)
+		_, err = am.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+	})
+	t.Run("Adapted", func(t *testing.T) {
+		am, err := m.Adapt(false, OverrideMappings{{FieldName: "Scores", CsvFieldName: "[5:6]"}})
+		require.NoError(t, err)
+		row, err := am.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, row.Scores)
+	})
+	t.Run("Written", func(t *testing.T) {
+		var buf strings.Builder
+		err := m.Writer(&buf).WriteAll([]testStruct{row})
+		require.Error(t, err)
+		require.Equal(t, `field with csv header "Phone*" cannot be written - expected to be map (field name: "Phones")`, err.Error())
+		am, err := m.Adapt(false, OverrideMappings{{FieldName: "Phones", CsvFieldName: "-Phone*"}})
+		require.NoError(t, err)
+		buf.Reset()
+		err = am.Writer(&buf).WriteAll([]testStruct{row, {Name: "Pippin", Scores: []int{1, 2, 3}, Rest: []string{"x"}}})
+		require.NoError(t, err)
+		require.Equal(t, `,,,,Scores[0],Scores[1],Scores[2],Rest[0],Rest[1],Name
+,,,,3,,,,,Sam
+,,,,1,2,3,x,,Pippin
+`, buf.String())
+		err = am.Writer(&buf).WriteAll([]testStruct{row, {Rest: []string{"x", "y", "z"}}})
+		require.Error(t, err)
+		require.Equal(t, `slice length 3 exceeds the 2 csv fields written (field name: "Rest")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_PivotMaps(t *testing.T) {
+	type testStruct struct {
+		Product  string               `csv:"Product"`
+		ByDate   map[string]float64   `csv:"[2:4],omitempty"`
+		ByRegion map[string]int       `csv:"Region *"`
+		Flags    map[string]*bool     `csv:"~^Is "`
+		Dates    map[string]time.Time `csv:"Launched*,layout=2006-01-02"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Product,2025-01-01,2025-01-02,2025-01-03,Region North,Region South,Is New,Is Sale,Launched UK,Launched US
+Widget,1.5,,3,10,20,true,,2024-12-01,2025-02-01`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, "Widget", row.Product)
+	require.Equal(t, map[string]float64{"2025-01-01": 1.5, "2025-01-03": 3}, row.ByDate)
+	require.Equal(t, map[string]int{"Region North": 10, "Region South": 20}, row.ByRegion)
+	require.Len(t, row.Flags, 2)
+	require.True(t, *row.Flags["Is New"])
+	require.Nil(t, row.Flags["Is Sale"])
+	require.Equal(t, map[string]time.Time{
+		"Launched UK": time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		"Launched US": time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, row.Dates)
+
+	t.Run("Bad value", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Product,2025-01-01,Region North,Is New,Launched UK\nWidget,x,1,,2025-01-01"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "x" to float64`, err.Error())
+	})
+	t.Run("Headers required", func(t *testing.T) {
+		type testStruct struct {
+			Values map[string]string `csv:"[1:]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader("a,b"), nil, csv.NoHeader(true)).Read()
+		require.Error(t, err)
+		require.Equal(t, "csv headers not present", err.Error())
+		row, err := m.Reader(strings.NewReader("A,B\na,b"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"A": "a", "B": "b"}, row.Values)
+	})
+	t.Run("Written", func(t *testing.T) {
+		var buf strings.Builder
+		err := m.Writer(&buf).WriteAll([]testStruct{row})
+		require.NoError(t, err)
+		require.Equal(t, `,2025-01-01,2025-01-03,,Product,Region North,Region South,Is New,Is Sale,Launched UK,Launched US
+,1.5,3,,Widget,10,20,true,,2024-12-01,2025-02-01
+`, buf.String())
+		written, err := m.Reader(strings.NewReader(buf.String()), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, row, written)
+		other := row
+		other.ByRegion = map[string]int{"Region East": 1}
+		err = m.Writer(&buf).WriteAll([]testStruct{row, other})
+		require.Error(t, err)
+		require.Equal(t, `map key "Region East" is not a csv header written (field name: "ByRegion")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_NestedPointers(t *testing.T) {
+	type Address struct {
+		Street   string   `csv:"Street"`
+		Postcode *string  `csv:"Postcode"`
+		Tags     []string `csv:"~^Tag"`
+	}
+	type Base struct {
+		Id int `csv:"Id"`
+	}
+	type testStruct struct {
+		*Base
+		Name    string `csv:"Name"`
+		Address *Address
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Id,Name,Street,Postcode,Tag1
+1,Bilbo,Bagshot Row,,
+2,Frodo,,HB1,
+,Sam,,,
+3,Merry,,,x`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 4)
+	require.Equal(t, 1, recs[0].Id)
+	require.NotNil(t, recs[0].Address)
+	require.Equal(t, "Bagshot Row", recs[0].Address.Street)
+	require.Nil(t, recs[0].Address.Postcode)
+	require.NotNil(t, recs[1].Address)
+	require.Equal(t, "HB1", *recs[1].Address.Postcode)
+	require.Nil(t, recs[2].Base)
+	require.Equal(t, "Sam", recs[2].Name)
+	require.Nil(t, recs[2].Address)
+	require.NotNil(t, recs[3].Address)
+	require.Equal(t, []string{"x"}, recs[3].Address.Tags)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs[:3])
+	require.Error(t, err)
+	wm, err := m.Adapt(false, OverrideMappings{{FieldName: "Address.Tags", CsvFieldName: "-~^Tag"}})
+	require.NoError(t, err)
+	err = wm.Writer(&buf).WriteAll(recs[:3])
+	require.NoError(t, err)
+	require.Equal(t, `Id,Name,Street,Postcode
+1,Bilbo,Bagshot Row,
+2,Frodo,,HB1
+,Sam,,
+`, buf.String())
+
+	t.Run("Allocated before fields are set", func(t *testing.T) {
+		type Addr struct {
+			Num     int    `csv:"num"`
+			Street  string `csv:"street,required"`
+			City    string `csv:"city,trim"`
+			Country string `csv:"country,default=UK"`
+		}
+		type testStruct struct {
+			Name string `csv:"name"`
+			A    *Addr
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		dm, err := m.Adapt(false, nil, DefaultEmptyValues(true))
+		require.NoError(t, err)
+		const hdrs = "name,city,street,num,country\n"
+		// repeated - as allocation must not depend on the order in which fields are set...
+		for n := 0; n < 50; n++ {
+			_, err = m.Reader(strings.NewReader(hdrs+"x,,s,,"), nil).Read()
+			require.Error(t, err)
+			require.Equal(t, `cannot convert value "" to int`, err.Error())
+			_, err = m.Reader(strings.NewReader(hdrs+"x,c,,1,"), nil).Read()
+			require.Error(t, err)
+			require.Equal(t, `value required`, err.Error())
+			row, err := dm.Reader(strings.NewReader(hdrs+"x,,s,,"), nil).Read()
+			require.NoError(t, err)
+			require.Equal(t, &Addr{Street: "s", Country: "UK"}, row.A)
+			row, err = m.Reader(strings.NewReader(hdrs+"x, ,,,"), nil).Read()
+			require.NoError(t, err)
+			require.Nil(t, row.A)
+		}
+	})
+	t.Run("Recursive", func(t *testing.T) {
+		type Node struct {
+			Name string
+			Next *Node
+		}
+		_, err := NewMapper[Node]()
+		require.Error(t, err)
+		require.Equal(t, `recursive struct field not supported (field name: "Next")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_NestedPrefixes(t *testing.T) {
+	type Address struct {
+		Street   string   `csv:"street|addr1"`
+		Postcode string   `csv:"postcode"`
+		Phones   []string `csv:"~phone,optional"`
+	}
+	type Contact struct {
+		Name    string   `csv:"name"`
+		Address *Address `csv:"home_,prefix"`
+	}
+	type testStruct struct {
+		Billing  Address  `csv:"billing_,prefix"`
+		Shipping *Address `csv:"shipping_,prefix"`
+		Contact  Contact  `csv:"contact_,prefix"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	require.Equal(t, OverrideMappings{
+		{FieldName: "Billing.Street", CsvFieldName: "billing_street|billing_addr1"},
+		{FieldName: "Billing.Postcode", CsvFieldName: "billing_postcode"},
+		{FieldName: "Billing.Phones", CsvFieldName: "~^billing_(?:.*(?:phone))"},
+		{FieldName: "Shipping.Street", CsvFieldName: "shipping_street|shipping_addr1"},
+		{FieldName: "Shipping.Postcode", CsvFieldName: "shipping_postcode"},
+		{FieldName: "Shipping.Phones", CsvFieldName: "~^shipping_(?:.*(?:phone))"},
+		{FieldName: "Contact.Name", CsvFieldName: "contact_name"},
+		{FieldName: "Contact.Address.Street", CsvFieldName: "contact_home_street|contact_home_addr1"},
+		{FieldName: "Contact.Address.Postcode", CsvFieldName: "contact_home_postcode"},
+		{FieldName: "Contact.Address.Phones", CsvFieldName: "~^contact_home_(?:.*(?:phone))"},
+	}, m.Mappings())
+
+	const data = `billing_addr1,billing_postcode,billing_phone,shipping_street,shipping_postcode,contact_name,contact_home_street,contact_home_postcode,contact_home_mobile_phone
+1 Main St,AB1,123,,,Bilbo,Bag End,HB1,456`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, Address{Street: "1 Main St", Postcode: "AB1", Phones: []string{"123"}}, row.Billing)
+	require.Nil(t, row.Shipping)
+	require.Equal(t, "Bilbo", row.Contact.Name)
+	require.Equal(t, &Address{Street: "Bag End", Postcode: "HB1", Phones: []string{"456"}}, row.Contact.Address)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll([]testStruct{row})
+	require.Error(t, err)
+	wm, err := m.Adapt(false, OverrideMappings{
+		{FieldName: "Billing.Phones", CsvFieldName: "-~^billing_(?:.*(?:phone))"},
+		{FieldName: "Shipping.Phones", CsvFieldName: "-~^shipping_(?:.*(?:phone))"},
+		{FieldName: "Contact.Address.Phones", CsvFieldName: "-~^contact_home_(?:.*(?:phone))"},
+	})
+	require.NoError(t, err)
+	err = wm.Writer(&buf).WriteAll([]testStruct{row})
+	require.NoError(t, err)
+	require.Equal(t, `billing_street,billing_postcode,shipping_street,shipping_postcode,contact_name,contact_home_street,contact_home_postcode
+1 Main St,AB1,,,Bilbo,Bag End,HB1
+`, buf.String())
+
+	t.Run("Untagged fields", func(t *testing.T) {
+		type Addr struct {
+			Street string
+			City   string `csv:",trim"`
+		}
+		type testStruct struct {
+			Id       int
+			Billing  Addr  `csv:"billing_,prefix"`
+			Shipping *Addr `csv:"shipping_,prefix"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		require.Equal(t, OverrideMappings{
+			{FieldName: "Id", CsvFieldIndex: 1},
+			{FieldName: "Billing.Street", CsvFieldName: "billing_Street"},
+			{FieldName: "Billing.City", CsvFieldName: "billing_City"},
+			{FieldName: "Shipping.Street", CsvFieldName: "shipping_Street"},
+			{FieldName: "Shipping.City", CsvFieldName: "shipping_City"},
+		}, m.Mappings())
+		row, err := m.Reader(strings.NewReader("Id,shipping_City,billing_Street,billing_City,shipping_Street\n1, Bree ,Bag End,Hobbiton,"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, testStruct{Id: 1, Billing: Addr{Street: "Bag End", City: "Hobbiton"}, Shipping: &Addr{City: "Bree"}}, row)
+	})
+	t.Run("Indexed fields", func(t *testing.T) {
+		type Addr struct {
+			Street string `csv:"[2]"`
+		}
+		type testStruct struct {
+			Billing Addr `csv:"billing_,prefix"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `csv field index [2] not supported within nested struct with "prefix" option (field name: "Billing.Street")`, err.Error())
+		type rangeStruct struct {
+			Billing struct {
+				Lines []string `csv:"[2:4]"`
+			} `csv:"billing_,prefix"`
+		}
+		_, err = NewMapper[rangeStruct]()
+		require.Error(t, err)
+		require.Equal(t, `csv field index [2:4] not supported within nested struct with "prefix" option (field name: "Billing.Lines")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_NumberFormats(t *testing.T) {
+	type testStruct struct {
+		Amount   float64   `csv:"Amount"`
+		Price    float64   `csv:"Price,decimal='.',grouping=',',currency=$|USD,parens"`
+		Quantity int       `csv:"Quantity"`
+		Units    *uint     `csv:"Units,grouping=.,decimal=','"`
+		Rate     float32   `csv:"Rate,percent"`
+		Share    int       `csv:"Share,percent"`
+		Weights  []float64 `csv:"Weights,sep=;"`
+	}
+	m, err := NewMapper[testStruct](NumberFormat{DecimalSeparator: ",", GroupingSeparator: ".", CurrencySymbols: []string{"€"}})
+	require.NoError(t, err)
+
+	const data = `Amount,Price,Quantity,Units,Rate,Share,Weights
+"1.234,56 €","($1,200.50)",1.000,2.500,"12,5%",50%,"1,5;2,25"`
+	row, err := m.Reader(strings.NewReader(data), nil).Read()
+	require.NoError(t, err)
+	require.Equal(t, 1234.56, row.Amount)
+	require.Equal(t, -1200.5, row.Price)
+	require.Equal(t, 1000, row.Quantity)
+	require.Equal(t, uint(2500), *row.Units)
+	require.Equal(t, float32(0.125), row.Rate)
+	require.Equal(t, 50, row.Share)
+	require.Equal(t, []float64{1.5, 2.25}, row.Weights)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll([]testStruct{row})
+	require.NoError(t, err)
+	require.Equal(t, `Amount,Price,Quantity,Units,Rate,Share,Weights
+"1234,56",-1200.5,1000,2500,"12,5%",50%,"1,5;2,25"
+`, buf.String())
+
+	t.Run("Bad value", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Amount,Price,Quantity,Units,Rate,Share,Weights\n1,1,1,1,1,x%,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "x%" to int`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_BoolValues(t *testing.T) {
+	type testStruct struct {
+		Active   bool  `csv:"Active"`
+		Enabled  *bool `csv:"Enabled,true=on,false=off"`
+		Selected bool  `csv:"Selected,true=X,false=''"`
+		Flagged  *bool `csv:"Flagged,true=X,false=''"`
+		Strict   *bool `csv:"Strict,true=1"`
+	}
+	m, err := NewMapper[testStruct](BoolValues{True: []string{"Y", "yes"}, False: []string{"N", "no"}})
+	require.NoError(t, err)
+
+	const data = `Active,Enabled,Selected,Flagged,Strict
+y,ON,x,X,1
+No,,,,no`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.True(t, recs[0].Active)
+	require.True(t, *recs[0].Enabled)
+	require.True(t, recs[0].Selected)
+	require.True(t, *recs[0].Flagged)
+	require.True(t, *recs[0].Strict)
+	require.False(t, recs[1].Active)
+	require.Nil(t, recs[1].Enabled)
+	require.False(t, recs[1].Selected)
+	require.NotNil(t, recs[1].Flagged)
+	require.False(t, *recs[1].Flagged)
+	require.False(t, *recs[1].Strict)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, `Active,Enabled,Selected,Flagged,Strict
+Y,on,X,X,1
+N,,,"",N
+`, buf.String())
+
+	t.Run("Bad value", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Active,Enabled,Selected,Flagged,Strict\ntrue,,,,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "true" to bool`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_NullValues(t *testing.T) {
+	type Address struct {
+		Street string `csv:"Street"`
+	}
+	type testStruct struct {
+		Name    *string  `csv:"Name"`
+		Age     *int     `csv:"Age"`
+		Score   float64  `csv:"Score"`
+		Notes   string   `csv:"Notes,null=-"`
+		Count   int      `csv:"Count,null=-,default=1"`
+		Values  []*int   `csv:"Values,sep=;"`
+		Address *Address `csv:"home_,prefix"`
+	}
+	m, err := NewMapper[testStruct](NullValues{"NULL", "N/A", `\N`}, DefaultEmptyValues(true))
+	require.NoError(t, err)
+
+	const data = `Name,Age,Score,Notes,Count,Values,home_Street
+NULL,N/A,\N,-,-,1;NULL;3,NULL
+"NULL",42,1.5,NULL,2,,Bag End`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Nil(t, recs[0].Name)
+	require.Nil(t, recs[0].Age)
+	require.Equal(t, 0.0, recs[0].Score)
+	require.Equal(t, "", recs[0].Notes)
+	require.Equal(t, 1, recs[0].Count)
+	require.Len(t, recs[0].Values, 3)
+	require.Nil(t, recs[0].Values[1])
+	require.Equal(t, 3, *recs[0].Values[2])
+	require.Nil(t, recs[0].Address)
+	require.Nil(t, recs[1].Name)
+	require.Equal(t, 42, *recs[1].Age)
+	require.Equal(t, "NULL", recs[1].Notes)
+	require.Equal(t, "Bag End", recs[1].Address.Street)
+
+	t.Run("Without DefaultEmptyValues", func(t *testing.T) {
+		am, err := m.Adapt(false, nil, DefaultEmptyValues(false))
+		require.NoError(t, err)
+		_, err = am.Reader(strings.NewReader(data), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "\\N" to float64`, err.Error())
+		var fe *FieldError
+		require.True(t, errors.As(err, &fe))
+		require.Equal(t, `\N`, fe.Value)
+	})
+}
+
+func TestReaderContext_Read_IntBases(t *testing.T) {
+	type testStruct struct {
+		Id      uint32 `csv:"Id,base=16"`
+		Auto    int    `csv:"Auto,base=0"`
+		Mode    *int   `csv:"Mode,base=8"`
+		Flags   uint8  `csv:"Flags,base=2"`
+		Count   int64  `csv:"Count,base=10"`
+		Default int    `csv:"Default"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Id,Auto,Mode,Flags,Count,Default
+0x1F,0b1010,0o755,1010_1010,1_000_000,42
+ff,-0x10,,0b1,1,0`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, uint32(31), recs[0].Id)
+	require.Equal(t, 10, recs[0].Auto)
+	require.Equal(t, 0755, *recs[0].Mode)
+	require.Equal(t, uint8(170), recs[0].Flags)
+	require.Equal(t, int64(1000000), recs[0].Count)
+	require.Equal(t, uint32(255), recs[1].Id)
+	require.Equal(t, -16, recs[1].Auto)
+	require.Nil(t, recs[1].Mode)
+	require.Equal(t, uint8(1), recs[1].Flags)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, `Id,Auto,Mode,Flags,Count,Default
+1f,10,755,10101010,1000000,42
+ff,-16,,1,1,0
+`, buf.String())
+
+	t.Run("Bad value", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Id,Auto,Mode,Flags,Count,Default\n1,1,1,2,1,1"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "2" to uint8`, err.Error())
+	})
+	t.Run("Underscores require base", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Id,Auto,Mode,Flags,Count,Default\n1,1,1,1,1,1_0"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "1_0" to int`, err.Error())
+	})
+	t.Run("Bad base", func(t *testing.T) {
+		type testStruct struct {
+			Id int `csv:"Id,base=37"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `invalid csv tag option base="37" (field name: "Id")`, err.Error())
+	})
+}
+
+func TestReaderContext_Read_BigNumbers(t *testing.T) {
+	type testStruct struct {
+		Int     big.Int    `csv:"Int"`
+		IntPtr  *big.Int   `csv:"IntPtr,base=16"`
+		Float   *big.Float `csv:"Float"`
+		Prec    big.Float  `csv:"Prec,prec=24"`
+		Rat     *big.Rat   `csv:"Rat"`
+		Complex complex128 `csv:"Complex"`
+		Small   *complex64 `csv:"Small"`
+		Amount  *big.Float `csv:"Amount,grouping=.,decimal=','"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount
+123456789012345678901234567890,ffffffffffffffffffff,3.14159265358979323846264338327950288,0.1,1/3,1+2i,3i,"1.234.567.890.123.456.789,01"
+-1,,,1,0.25,(-1.5-0.5i),,`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, "123456789012345678901234567890", recs[0].Int.String())
+	require.Equal(t, "1208925819614629174706175", recs[0].IntPtr.String())
+	require.Equal(t, "3.14159265358979323846264338327950288", recs[0].Float.Text('f', -1))
+	require.Equal(t, uint(24), recs[0].Prec.Prec())
+	require.Equal(t, "1/3", recs[0].Rat.RatString())
+	require.Equal(t, complex(1, 2), recs[0].Complex)
+	require.Equal(t, complex64(complex(0, 3)), *recs[0].Small)
+	require.Equal(t, "1234567890123456789.01", recs[0].Amount.Text('f', 2))
+	require.Equal(t, "-1", recs[1].Int.String())
+	require.Nil(t, recs[1].IntPtr)
+	require.Nil(t, recs[1].Float)
+	require.Equal(t, "1/4", recs[1].Rat.RatString())
+	require.Equal(t, complex(-1.5, -0.5), recs[1].Complex)
+	require.Nil(t, recs[1].Small)
+	require.Nil(t, recs[1].Amount)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, `Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount
+123456789012345678901234567890,ffffffffffffffffffff,3.14159265358979323846264338327950288,0.1,1/3,(1+2i),(0+3i),"1234567890123456789,01"
+-1,,,1,1/4,(-1.5-0.5i),,
+`, buf.String())
+
+	t.Run("Overflows", func(t *testing.T) {
+		type testStruct struct {
+			Int8    int8      `csv:"Int8"`
+			Uint    uint      `csv:"Uint"`
+			Float   float32   `csv:"Float"`
+			Complex complex64 `csv:"Complex"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader("Int8,Uint,Float,Complex\n128,0,0,0"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `value "128" overflows int8`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int8,Uint,Float,Complex\n0,-1,0,0"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "-1" to uint`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int8,Uint,Float,Complex\n0,18446744073709551616,0,0"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `value "18446744073709551616" overflows uint`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int8,Uint,Float,Complex\n0,0,1e39,0"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `value "1e39" overflows float32`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int8,Uint,Float,Complex\n0,0,0,1e39i"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `value "1e39i" overflows complex64`, err.Error())
+	})
+	t.Run("Bad values", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount\nx,,,0,0,0,,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "x" to big.Int`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount\n0,,,x,0,0,,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "x" to big.Float`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount\n0,,,0,1/0,0,,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "1/0" to big.Rat`, err.Error())
+		_, err = m.Reader(strings.NewReader("Int,IntPtr,Float,Prec,Rat,Complex,Small,Amount\n0,,,0,0,x,,"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `cannot convert value "x" to complex128`, err.Error())
+	})
+	t.Run("Bad prec", func(t *testing.T) {
+		type testStruct struct {
+			Float big.Float `csv:"Float,prec=0"`
+		}
+		_, err := NewMapper[testStruct]()
+		require.Error(t, err)
+		require.Equal(t, `invalid csv tag option prec="0" (field name: "Float")`, err.Error())
+	})
+}
+func TestReaderContext_Read_Extra(t *testing.T) {
+	const data = `Id,Name,Colour,Size,Notes
+1,Widget,Red,Large,
+2,Gadget,Blue,,Fragile`
+	t.Run("By header", func(t *testing.T) {
+		type testStruct struct {
+			Id    int               `csv:"[1]"`
+			Name  string            `csv:"Name"`
+			Extra map[string]string `csv:"[extra]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+		require.Equal(t, map[string]string{"Colour": "Red", "Size": "Large", "Notes": ""}, recs[0].Extra)
+		require.Equal(t, map[string]string{"Colour": "Blue", "Size": "", "Notes": "Fragile"}, recs[1].Extra)
+	})
+	t.Run("By index", func(t *testing.T) {
+		type testStruct struct {
+			Id    int            `csv:"[1]"`
+			Name  string         `csv:"[2]"`
+			Extra map[int]string `csv:"[extra]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+		require.Equal(t, map[int]string{3: "Red", 4: "Large", 5: ""}, recs[0].Extra)
+		require.Equal(t, map[int]string{3: "Blue", 4: "", 5: "Fragile"}, recs[1].Extra)
+	})
+	t.Run("Header regex consumed", func(t *testing.T) {
+		type testStruct struct {
+			Name  string            `csv:"Name"`
+			Sizes []string          `csv:"~^(Colour|Size)$"`
+			Extra map[string]string `csv:"[extra]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader(data), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Red", "Large"}, row.Sizes)
+		require.Equal(t, map[string]string{"Id": "1", "Notes": ""}, row.Extra)
+	})
+	t.Run("No headers", func(t *testing.T) {
+		type testStruct struct {
+			Extra map[string]string `csv:"[extra]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader(data), nil, csv.NoHeader(true)).Read()
+		require.Error(t, err)
+		require.Equal(t, "csv headers not present", err.Error())
+	})
+}
+
+func TestReaderContext_Read_DisallowUnknownColumns(t *testing.T) {
+	type testStruct struct {
+		Id   int    `csv:"[1]"`
+		Name string `csv:"Name"`
+	}
+	const data = `Id,Name,Colour,Size
+1,Widget,Red,Large
+2,Gadget,Blue,Small`
+	m, err := NewMapper[testStruct](DisallowUnknownColumns(true))
+	require.NoError(t, err)
+	r := m.Reader(strings.NewReader(data), nil)
+	_, err = r.Read()
+	require.Error(t, err)
+	require.Equal(t, `csv headers "Colour", "Size" unknown`, err.Error())
+	_, err = r.Read()
+	require.Error(t, err)
+
+	t.Run("All known", func(t *testing.T) {
+		recs, err := m.Reader(strings.NewReader("Id,Name\n1,Widget"), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 1)
+	})
+	t.Run("With extra", func(t *testing.T) {
+		type testStruct struct {
+			Name  string            `csv:"Name"`
+			Extra map[string]string `csv:"[extra]"`
+		}
+		m, err := NewMapper[testStruct](DisallowUnknownColumns(true))
+		require.NoError(t, err)
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+	})
+	t.Run("Supplied headers", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("1,Widget,Red"), nil, csv.NoHeader(true)).SupplyHeaders([]string{"Id", "Name", "Colour"}).Read()
+		require.Error(t, err)
+		require.Equal(t, `csv header "Colour" unknown`, err.Error())
+	})
+	t.Run("Adapted", func(t *testing.T) {
+		am, err := m.Adapt(false, nil, DisallowUnknownColumns(false))
+		require.NoError(t, err)
+		recs, err := am.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+	})
+}
+
+func TestReaderContext_ValidateHeaders(t *testing.T) {
+	type testStruct struct {
+		Id      int    `csv:"[1]"`
+		Name    string `csv:"Name"`
+		Amount  string `csv:"Amount"`
+		Colour  string `csv:"Colour|Color"`
+		Size    string `csv:"Size"`
+		Country string `csv:"Country,optional"`
+	}
+	m, err := NewMapper[testStruct](DisallowUnknownColumns(true), DisallowDuplicateHeaders(true))
+	require.NoError(t, err)
+	const data = `Id,Name,Amount,Amount,Color,Notes,Ref
+1,Widget,10,20,Red,,`
+	r := m.Reader(strings.NewReader(data), nil)
+	err = r.ValidateHeaders()
+	require.Error(t, err)
+	var hdrsErr *HeadersError
+	require.True(t, errors.As(err, &hdrsErr))
+	require.Equal(t, []string{"Size"}, hdrsErr.Missing)
+	require.Equal(t, []string{"Amount"}, hdrsErr.Duplicate)
+	require.Equal(t, []string{"Amount", "Notes", "Ref"}, hdrsErr.Unexpected)
+	require.Equal(t, `csv header "Size" not present; csv header "Amount" duplicated; csv headers "Amount", "Notes", "Ref" unknown`, err.Error())
+	_, err = r.Read()
+	require.Error(t, err)
+	require.True(t, errors.As(err, &hdrsErr))
+	_, err = m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.Error(t, err)
+	require.True(t, errors.As(err, &hdrsErr))
+
+	t.Run("Valid", func(t *testing.T) {
+		r := m.Reader(strings.NewReader("Id,Name,Amount,Colour,Size\n1,Widget,10,Red,Large"), nil)
+		require.NoError(t, r.ValidateHeaders())
+		row, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, 1, row.Id)
+		require.Equal(t, "Large", row.Size)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		err := m.Reader(strings.NewReader(""), nil).ValidateHeaders()
+		require.Error(t, err)
+		require.Equal(t, "csv headers not present", err.Error())
+	})
+	t.Run("No headers required", func(t *testing.T) {
+		type testStruct struct {
+			Id int `csv:"[1]"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		r := m.Reader(strings.NewReader("1\n2"), nil, csv.NoHeader(true))
+		require.NoError(t, r.ValidateHeaders())
+		recs, err := r.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+	})
+}
+
+func TestReaderContext_Read_DuplicateHeaders(t *testing.T) {
+	const data = `Date,Amount,Description,Amount,Balance
+2025-01-02,10.50,Coffee,,89.50
+2025-01-03,,Refund,5.00,94.50`
+	type testStruct struct {
+		Date        string   `csv:"Date"`
+		Debit       *float64 `csv:"Amount#1"`
+		Credit      *float64 `csv:"Amount#2"`
+		Description string   `csv:"Description"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, 10.5, *recs[0].Debit)
+	require.Nil(t, recs[0].Credit)
+	require.Nil(t, recs[1].Debit)
+	require.Equal(t, 5.0, *recs[1].Credit)
+
+	t.Run("Occurrence not present", func(t *testing.T) {
+		_, err := m.Reader(strings.NewReader("Date,Amount,Description\n2025-01-02,10.50,Coffee"), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `csv header "Amount#2" not present`, err.Error())
+	})
+	t.Run("Referenced duplicate uses last occurrence", func(t *testing.T) {
+		type testStruct struct {
+			Amount *float64 `csv:"Amount"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+		require.Nil(t, recs[0].Amount)
+		require.Equal(t, 5.0, *recs[1].Amount)
+		dm, err := m.Adapt(false, nil, DisallowDuplicateHeaders(true))
+		require.NoError(t, err)
+		_, err = dm.Reader(strings.NewReader(data), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `csv header "Amount" duplicated`, err.Error())
+	})
+	t.Run("Unreferenced duplicates allowed", func(t *testing.T) {
+		type testStruct struct {
+			Date string `csv:"Date"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, recs, 2)
+	})
+	t.Run("Disallowed", func(t *testing.T) {
+		m, err := NewMapper[testStruct](DisallowDuplicateHeaders(true))
+		require.NoError(t, err)
+		_, err = m.Reader(strings.NewReader(data), nil).Read()
+		require.Error(t, err)
+		require.Equal(t, `csv header "Amount" duplicated`, err.Error())
+	})
+	t.Run("Literal header with occurrence suffix", func(t *testing.T) {
+		type testStruct struct {
+			Item string `csv:"Item#2"`
+		}
+		m, err := NewMapper[testStruct]()
+		require.NoError(t, err)
+		row, err := m.Reader(strings.NewReader("Item,Item#2\nA,B"), nil).Read()
+		require.NoError(t, err)
+		require.Equal(t, "B", row.Item)
+	})
+	t.Run("Writes header without occurrence", func(t *testing.T) {
+		var buf strings.Builder
+		err := m.Writer(&buf).WriteAll(recs)
+		require.NoError(t, err)
+		require.Equal(t, `Date,Amount,Amount,Description
+2025-01-02,10.5,,Coffee
+2025-01-03,,5,Refund
+`, buf.String())
+	})
+}
+
 type testErrorHandler struct {
 	errs  []error
 	lines []int
@@ -486,3 +1766,57 @@ func (h *testErrorHandler) Handle(err error, line int) error {
 	h.lines = append(h.lines, line)
 	return nil
 }
+
+type testSliceUnmarshaler struct {
+	value string
+}
+
+func (u *testSliceUnmarshaler) UnmarshalCSV(val string, record []string) error {
+	u.value = strings.ToUpper(val)
+	return nil
+}
+
+func (u *testSliceUnmarshaler) MarshalCSV(record []string) (string, error) {
+	return strings.ToLower(u.value), nil
+}
+
+func TestReaderContext_Read_Slices(t *testing.T) {
+	type testStruct struct {
+		Ints    []int                  `csv:"Ints,sep=;,trim"`
+		Floats  *[]float64             `csv:"Floats,sep=;"`
+		Bools   []bool                 `csv:"Bools"`
+		Dates   []time.Time            `csv:"Dates,layout=2006-01-02"`
+		Codes   []testSliceUnmarshaler `csv:"Codes"`
+		Strings []string               `csv:"Strings"`
+	}
+	m, err := NewMapper[testStruct](DefaultSliceSeparator("|"))
+	require.NoError(t, err)
+
+	const data = `Ints,Floats,Bools,Dates,Codes,Strings
+1; 2 ;3,1.5;2,true|false,2025-01-02|2025-01-03,ab|cd,a|b
+,,,,,`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, []int{1, 2, 3}, recs[0].Ints)
+	require.Equal(t, []float64{1.5, 2}, *recs[0].Floats)
+	require.Equal(t, []bool{true, false}, recs[0].Bools)
+	require.Equal(t, []time.Time{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)}, recs[0].Dates)
+	require.Equal(t, []testSliceUnmarshaler{{value: "AB"}, {value: "CD"}}, recs[0].Codes)
+	require.Equal(t, []string{"a", "b"}, recs[0].Strings)
+	require.Empty(t, recs[1].Ints)
+	require.Nil(t, recs[1].Floats)
+	require.Empty(t, recs[1].Strings)
+
+	var buf strings.Builder
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, `Ints,Floats,Bools,Dates,Codes,Strings
+1;2;3,1.5;2,true|false,2025-01-02|2025-01-03,ab|cd,a|b
+,,,,,
+`, buf.String())
+
+	_, err = m.Reader(strings.NewReader("Ints,Floats,Bools,Dates,Codes,Strings\n1;x,,,,,"), nil).Read()
+	require.Error(t, err)
+	require.Equal(t, `cannot convert value "x" to int`, err.Error())
+}
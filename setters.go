@@ -2,344 +2,374 @@ package csvamp
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
-func buildSetter[T any](currentPath []int, fld reflect.StructField) (func(t *T, val string, quoted bool, defEmpties bool, record []string) error, error) {
-	fk := fld.Type.Kind()
+// valueSetter sets a value (i.e. a struct field, slice element or map value) from a CSV field value
+type valueSetter func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error
+
+func buildSetter[T any](currentPath []int, fld reflect.StructField, opts *fieldOptions) (func(t *T, val string, quoted bool, defEmpties bool, record []string) error, error) {
+	vs, err := buildValueSetter(fld.Type, opts)
+	if err != nil {
+		return nil, err
+	}
+	vs = optionsValueSetter(vs, opts)
+	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
+		// pointers to nested structs have already been allocated (if any of their mapped csv fields are non-empty) - fields of unallocated nested structs are not set...
+		if v, ok := fieldByPath(reflect.ValueOf(t).Elem(), currentPath, false); ok {
+			return vs(v, val, quoted, defEmpties, record)
+		}
+		return nil
+	}, nil
+}
+
+// fieldByPath returns the struct field value at the field path
+//
+// nil pointers to (nested or embedded) structs along the path are allocated - unless alloc is false, in which case the field is not reachable (and false is returned)
+func fieldByPath(v reflect.Value, path []int, alloc bool) (reflect.Value, bool) {
+	for i, idx := range path {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+func buildValueSetter(typ reflect.Type, opts *fieldOptions) (vs valueSetter, err error) {
+	if vs, err = buildTypeValueSetter(typ, opts); err == nil && typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Slice {
+		if allowed := opts.enumValues(typ); allowed != nil {
+			vs = enumValueSetter(vs, allowed)
+		}
+	}
+	return vs, err
+}
+
+func buildTypeValueSetter(typ reflect.Type, opts *fieldOptions) (valueSetter, error) {
+	if vs, ok := opts.converter(typ); ok {
+		return vs, nil
+	}
+	fk := typ.Kind()
 	if fk == reflect.Ptr {
-		return buildPtrSetter[T](currentPath, fld)
+		return buildPtrValueSetter(typ, opts)
 	}
-	if isUnmarshalerCsvType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			// create pointer to the field...
-			ptr := reflect.New(fld.Type)
+	switch typ {
+	case timeType:
+		return setterTime(opts.timeLayout(), opts.timeLocation()), nil
+	case durationType:
+		return setterDuration, nil
+	case bigIntType:
+		return setterBigInt(opts.intBase(), opts.numberFormat()), nil
+	case bigFloatType:
+		return setterBigFloat(opts.floatPrecision(), opts.numberFormat()), nil
+	case bigRatType:
+		return setterBigRat(opts.numberFormat()), nil
+	}
+	if isUnmarshalerCsvType(typ) {
+		return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+			// create pointer to the value...
+			ptr := reflect.New(typ)
 			// call UnmarshalCSV on the pointer...
 			u := ptr.Interface().(CsvUnmarshaler)
 			if err := u.UnmarshalCSV(val, record); err != nil {
 				return err
 			}
-			// assign dereferenced result back to field...
+			// assign dereferenced result back to value...
 			v.Set(ptr.Elem())
 			return nil
 		}, nil
-	} else if isUnmarshalerQuotedCsvType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			// create pointer to the field...
-			ptr := reflect.New(fld.Type)
+	} else if isUnmarshalerQuotedCsvType(typ) {
+		return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+			// create pointer to the value...
+			ptr := reflect.New(typ)
 			// call UnmarshalQuotedCSV on the pointer...
 			u := ptr.Interface().(CsvQuotedUnmarshaler)
 			if err := u.UnmarshalQuotedCSV(val, quoted, record); err != nil {
 				return err
 			}
-			// assign dereferenced result back to field...
+			// assign dereferenced result back to value...
 			v.Set(ptr.Elem())
 			return nil
 		}, nil
-	} else if isUnmarshalerTextType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			// create pointer to the field...
-			ptr := reflect.New(fld.Type)
+	} else if isUnmarshalerTextType(typ) {
+		return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+			// create pointer to the value...
+			ptr := reflect.New(typ)
 			// call UnmarshalText on the pointer...
 			u := ptr.Interface().(encoding.TextUnmarshaler)
 			if err := u.UnmarshalText([]byte(val)); err != nil {
 				return err
 			}
-			// assign dereferenced result back to field...
+			// assign dereferenced result back to value...
 			v.Set(ptr.Elem())
 			return nil
 		}, nil
 	}
 	switch fk {
 	case reflect.Bool:
-		return setterBool[T](currentPath), nil
+		if bt := opts.boolTokens(); bt != nil {
+			return setterBoolTokens(bt), nil
+		}
+		return setterBool, nil
 	case reflect.Int:
-		return setterInt[T](currentPath, 0), nil
+		return setterInt(0, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Int8:
-		return setterInt[T](currentPath, 8), nil
+		return setterInt(8, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Int16:
-		return setterInt[T](currentPath, 16), nil
+		return setterInt(16, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Int32:
-		return setterInt[T](currentPath, 32), nil
+		return setterInt(32, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Int64:
-		return setterInt[T](currentPath, 64), nil
+		return setterInt(64, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Uint:
-		return setterUint[T](currentPath, 0), nil
+		return setterUint(0, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Uint8:
-		return setterUint[T](currentPath, 8), nil
+		return setterUint(8, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Uint16:
-		return setterUint[T](currentPath, 16), nil
+		return setterUint(16, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Uint32:
-		return setterUint[T](currentPath, 32), nil
+		return setterUint(32, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Uint64:
-		return setterUint[T](currentPath, 64), nil
+		return setterUint(64, opts.intBase(), opts.numberFormat()), nil
 	case reflect.Float32:
-		return setterFloat[T](currentPath, 32), nil
+		return setterFloat(32, opts.numberFormat()), nil
 	case reflect.Float64:
-		return setterFloat[T](currentPath, 64), nil
+		return setterFloat(64, opts.numberFormat()), nil
+	case reflect.Complex64:
+		return setterComplex(64), nil
+	case reflect.Complex128:
+		return setterComplex(128), nil
 	case reflect.String:
-		return setterString[T](currentPath), nil
+		return setterString, nil
 	case reflect.Slice:
-		if fld.Type.Elem().Kind() == reflect.String {
-			return setterSliceString[T](currentPath), nil
-		}
+		return buildSliceValueSetter(typ, opts)
 	}
 	return nil, fmt.Errorf("struct field unsupported type: %s", fk.String())
 }
 
-func setterBool[T any](currentPath []int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		if defEmpties && val == "" {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetBool(false)
-		} else if b, err := strconv.ParseBool(val); err != nil {
-			return fmt.Errorf("cannot convert value %q to bool", val)
+// optionsValueSetter wraps the value setter with the field options that pre-process the value (i.e. trim, null tokens, required and default)
+func optionsValueSetter(vs valueSetter, opts *fieldOptions) valueSetter {
+	if opts == nil || (!opts.trim && !opts.required && opts.defaultValue == nil && len(opts.nulls) == 0) {
+		return vs
+	}
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if opts.trim {
+			val = strings.TrimSpace(val)
+		}
+		token, null := val, opts.isNull(val)
+		if null {
+			// null token is an (unquoted) empty value...
+			val, quoted = "", false
+		}
+		if val == "" {
+			if opts.required {
+				return errors.New("value required")
+			} else if opts.defaultValue != nil {
+				val = *opts.defaultValue
+			}
+		}
+		err := vs(v, val, quoted, defEmpties, record)
+		if err != nil && null && val == "" {
+			// report the null token (rather than the empty value it is read as)...
+			return fmt.Errorf("cannot convert value %q to %s", token, v.Type().String())
+		}
+		return err
+	}
+}
+
+func setterBool(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+	if defEmpties && val == "" {
+		v.SetBool(false)
+	} else if b, err := strconv.ParseBool(val); err != nil {
+		return fmt.Errorf("cannot convert value %q to bool", val)
+	} else {
+		v.SetBool(b)
+	}
+	return nil
+}
+
+func setterBoolTokens(bt *boolTokens) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if b, ok := bt.parse(val); ok {
+			v.SetBool(b)
+		} else if defEmpties && val == "" {
+			v.SetBool(false)
 		} else {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetBool(b)
+			return fmt.Errorf("cannot convert value %q to bool", val)
 		}
 		return nil
 	}
 }
 
-func setterInt[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
+func setterInt(bitSize int, base *int, nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
 		if defEmpties && val == "" {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetInt(0)
-		} else if v, err := strconv.ParseInt(val, 10, bitSize); err != nil {
+			v.SetInt(0)
+		} else if i, err := strconv.ParseInt(parseInteger(val, base, nf), baseOf(base), bitSize); err != nil {
 			if bitSize == 0 {
-				return fmt.Errorf("cannot convert value %q to int", val)
+				return conversionError(val, "int", err)
 			} else {
-				return fmt.Errorf("cannot convert value %q to int%d", val, bitSize)
+				return conversionError(val, "int"+strconv.Itoa(bitSize), err)
 			}
 		} else {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetInt(v)
+			v.SetInt(i)
 		}
 		return nil
 	}
 }
 
-func setterUint[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
+func setterUint(bitSize int, base *int, nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
 		if defEmpties && val == "" {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetUint(0)
-		} else if v, err := strconv.ParseUint(val, 10, bitSize); err != nil {
+			v.SetUint(0)
+		} else if i, err := strconv.ParseUint(parseInteger(val, base, nf), baseOf(base), bitSize); err != nil {
 			if bitSize == 0 {
-				return fmt.Errorf("cannot convert value %q to uint", val)
+				return conversionError(val, "uint", err)
 			} else {
-				return fmt.Errorf("cannot convert value %q to uint%d", val, bitSize)
+				return conversionError(val, "uint"+strconv.Itoa(bitSize), err)
 			}
 		} else {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetUint(v)
+			v.SetUint(i)
 		}
 		return nil
 	}
 }
 
-func setterFloat[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
+func setterFloat(bitSize int, nf *NumberFormat) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
 		if defEmpties && val == "" {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetFloat(0)
-		} else if v, err := strconv.ParseFloat(val, bitSize); err != nil {
-			return fmt.Errorf("cannot convert value %q to float%d", val, bitSize)
+			v.SetFloat(0)
+		} else if f, err := strconv.ParseFloat(parseFloat(val, nf), bitSize); err != nil {
+			return conversionError(val, "float"+strconv.Itoa(bitSize), err)
 		} else {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetFloat(v)
+			v.SetFloat(f)
 		}
 		return nil
 	}
 }
 
-func setterString[T any](currentPath []int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		reflect.ValueOf(t).Elem().FieldByIndex(currentPath).SetString(val)
-		return nil
+// conversionError returns the error for a value that cannot be converted to a numeric type - distinguishing values that overflow the type
+func conversionError(val string, typeName string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("value %q overflows %s", val, typeName)
 	}
+	return fmt.Errorf("cannot convert value %q to %s", val, typeName)
 }
 
-func setterSliceString[T any](currentPath []int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		if val == "" {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).Set(reflect.ValueOf([]string{}))
-		} else {
-			reflect.ValueOf(t).Elem().FieldByIndex(currentPath).Set(reflect.ValueOf(strings.Split(val, ",")))
-		}
-		return nil
-	}
+func setterString(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+	v.SetString(val)
+	return nil
 }
 
-func buildPtrSetter[T any](currentPath []int, fld reflect.StructField) (func(t *T, val string, quoted bool, defEmpties bool, record []string) error, error) {
-	if isUnmarshalerCsvType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			if val == "" && !quoted {
-				v.Set(reflect.Zero(v.Type()))
-				return nil
-			}
-			if v.IsNil() {
-				v.Set(reflect.New(v.Type().Elem()))
-			}
-			u := v.Interface().(CsvUnmarshaler)
-			return u.UnmarshalCSV(val, record)
-		}, nil
-	} else if isUnmarshalerQuotedCsvType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			if val == "" && !quoted {
-				v.Set(reflect.Zero(v.Type()))
-				return nil
-			}
-			if v.IsNil() {
-				v.Set(reflect.New(v.Type().Elem()))
-			}
-			u := v.Interface().(CsvQuotedUnmarshaler)
-			return u.UnmarshalQuotedCSV(val, quoted, record)
-		}, nil
-	} else if isUnmarshalerTextType(fld.Type) {
-		return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-			v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-			if val == "" && !quoted {
-				v.Set(reflect.Zero(v.Type()))
-				return nil
-			}
-			if v.IsNil() {
-				v.Set(reflect.New(v.Type().Elem()))
-			}
-			u := v.Interface().(encoding.TextUnmarshaler)
-			return u.UnmarshalText([]byte(val))
-		}, nil
-	}
-	fk := fld.Type.Elem().Kind()
-	switch fk {
-	case reflect.Bool:
-		return setterPtrBool[T](currentPath), nil
-	case reflect.Int:
-		return setterPtrInt[T](currentPath, 0), nil
-	case reflect.Int8:
-		return setterPtrInt[T](currentPath, 8), nil
-	case reflect.Int16:
-		return setterPtrInt[T](currentPath, 16), nil
-	case reflect.Int32:
-		return setterPtrInt[T](currentPath, 32), nil
-	case reflect.Int64:
-		return setterPtrInt[T](currentPath, 64), nil
-	case reflect.Uint:
-		return setterPtrUint[T](currentPath, 0), nil
-	case reflect.Uint8:
-		return setterPtrUint[T](currentPath, 8), nil
-	case reflect.Uint16:
-		return setterPtrUint[T](currentPath, 16), nil
-	case reflect.Uint32:
-		return setterPtrUint[T](currentPath, 32), nil
-	case reflect.Uint64:
-		return setterPtrUint[T](currentPath, 64), nil
-	case reflect.Float32:
-		return setterPtrFloat[T](currentPath, 32), nil
-	case reflect.Float64:
-		return setterPtrFloat[T](currentPath, 64), nil
-	case reflect.String:
-		return setterPtrString[T](currentPath), nil
-	}
-	return nil, fmt.Errorf("struct field unsupported type: *%s", fk.String())
+func setterBytes(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+	v.SetBytes([]byte(val))
+	return nil
 }
 
-func setterPtrBool[T any](currentPath []int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-		if val == "" {
-			v.Set(reflect.Zero(v.Type()))
-			return nil
-		}
-		if b, err := strconv.ParseBool(val); err != nil {
-			return fmt.Errorf("cannot convert value %q to bool", val)
-		} else {
-			ptr := reflect.New(v.Type().Elem())
-			ptr.Elem().SetBool(b)
-			v.Set(ptr)
-		}
-		return nil
+// buildSliceValueSetter builds a value setter for slices - where the value is split (using the field option separator) and each part is set as an element
+func buildSliceValueSetter(typ reflect.Type, opts *fieldOptions) (valueSetter, error) {
+	et := typ.Elem()
+	switch et.Kind() {
+	case reflect.Uint8:
+		return setterBytes, nil
+	case reflect.Slice, reflect.Map:
+		return nil, fmt.Errorf("struct field unsupported type: []%s", et.Kind().String())
 	}
-}
-
-func setterPtrInt[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
+	es, err := buildValueSetter(et, opts)
+	if err != nil {
+		return nil, err
+	}
+	sep := opts.sliceSeparator()
+	trim := opts != nil && opts.trim
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
 		if val == "" {
-			v.Set(reflect.Zero(v.Type()))
+			v.Set(reflect.MakeSlice(typ, 0, 0))
 			return nil
 		}
-		if i, err := strconv.ParseInt(val, 10, bitSize); err != nil {
-			if bitSize == 0 {
-				return fmt.Errorf("cannot convert value %q to int", val)
-			} else {
-				return fmt.Errorf("cannot convert value %q to int%d", val, bitSize)
+		parts := strings.Split(val, sep)
+		sv := reflect.MakeSlice(typ, len(parts), len(parts))
+		for i, part := range parts {
+			if trim {
+				part = strings.TrimSpace(part)
+			}
+			if opts.isNull(part) {
+				part = ""
+			}
+			if err := es(sv.Index(i), part, false, defEmpties, record); err != nil {
+				return err
 			}
-		} else {
-			ptr := reflect.New(v.Type().Elem())
-			ptr.Elem().SetInt(i)
-			v.Set(ptr)
 		}
+		v.Set(sv)
 		return nil
-	}
+	}, nil
 }
 
-func setterPtrUint[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-		if val == "" {
-			v.Set(reflect.Zero(v.Type()))
-			return nil
-		}
-		if i, err := strconv.ParseUint(val, 10, bitSize); err != nil {
-			if bitSize == 0 {
-				return fmt.Errorf("cannot convert value %q to uint", val)
-			} else {
-				return fmt.Errorf("cannot convert value %q to uint%d", val, bitSize)
-			}
+func setterTime(layout string, loc *time.Location) valueSetter {
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if defEmpties && val == "" {
+			v.Set(reflect.Zero(timeType))
+		} else if dt, err := time.ParseInLocation(layout, val, loc); err != nil {
+			return fmt.Errorf("cannot convert value %q to time (layout %q)", val, layout)
 		} else {
-			ptr := reflect.New(v.Type().Elem())
-			ptr.Elem().SetUint(i)
-			v.Set(ptr)
+			v.Set(reflect.ValueOf(dt))
 		}
 		return nil
 	}
 }
 
-func setterPtrFloat[T any](currentPath []int, bitSize int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-		if val == "" {
-			v.Set(reflect.Zero(v.Type()))
-			return nil
-		}
-		if f, err := strconv.ParseFloat(val, bitSize); err != nil {
-			return fmt.Errorf("cannot convert value %q to float%d", val, bitSize)
-		} else {
-			ptr := reflect.New(v.Type().Elem())
-			ptr.Elem().SetFloat(f)
-			v.Set(ptr)
-		}
-		return nil
+func setterDuration(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+	if defEmpties && val == "" {
+		v.SetInt(0)
+	} else if d, err := time.ParseDuration(val); err != nil {
+		return fmt.Errorf("cannot convert value %q to duration", val)
+	} else {
+		v.SetInt(int64(d))
 	}
+	return nil
 }
 
-func setterPtrString[T any](currentPath []int) func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-	return func(t *T, val string, quoted bool, defEmpties bool, record []string) error {
-		v := reflect.ValueOf(t).Elem().FieldByIndex(currentPath)
-		if val == "" && !quoted {
+func buildPtrValueSetter(typ reflect.Type, opts *fieldOptions) (valueSetter, error) {
+	et := typ.Elem()
+	if et.Kind() == reflect.Ptr {
+		return nil, fmt.Errorf("struct field unsupported type: *%s", et.Kind().String())
+	}
+	es, err := buildValueSetter(et, opts)
+	if err != nil {
+		return nil, fmt.Errorf("struct field unsupported type: *%s", et.Kind().String())
+	}
+	// an empty quoted value sets a non-nil pointer for strings and unmarshalers (otherwise, an empty value is a nil pointer)...
+	quotedEmpties := et.Kind() == reflect.String || (isUnmarshalerType(typ) && !isBigType(et))
+	// an empty value is not nil for bools where an empty value is a bool token...
+	emptyIsValue := et.Kind() == reflect.Bool && opts.boolTokens().emptyIsValue()
+	return func(v reflect.Value, val string, quoted bool, defEmpties bool, record []string) error {
+		if val == "" && !(quoted && quotedEmpties) && !emptyIsValue {
 			v.Set(reflect.Zero(v.Type()))
 			return nil
 		}
-		ptr := reflect.New(v.Type().Elem())
-		ptr.Elem().SetString(val)
+		ptr := reflect.New(et)
+		if err := es(ptr.Elem(), val, quoted, defEmpties, record); err != nil {
+			return err
+		}
 		v.Set(ptr)
 		return nil
-	}
+	}, nil
 }
 
+var timeType = reflect.TypeOf(time.Time{})
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
 var unmarshalerCsvType = reflect.TypeOf((*CsvUnmarshaler)(nil)).Elem()
 
 var unmarshalerQuotedCsvType = reflect.TypeOf((*CsvQuotedUnmarshaler)(nil)).Elem()
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestBuildSetter(t *testing.T) {
@@ -182,6 +183,11 @@ func TestBuildSetter(t *testing.T) {
 			sample: struct {
 				Foo []int
 			}{},
+		},
+		{
+			sample: struct {
+				Foo [][]int
+			}{},
 			expectErr: true,
 		},
 		{
@@ -201,7 +207,7 @@ func TestBuildSetter(t *testing.T) {
 		t.Run(fmt.Sprintf("[%d]", i+1), func(t *testing.T) {
 			vo := reflect.TypeOf(tc.sample)
 			fld := vo.Field(0)
-			fn, err := buildSetter[any]([]int{0}, fld)
+			fn, err := buildSetter[any]([]int{0}, fld, nil)
 			if tc.expectErr {
 				require.Error(t, err)
 				require.Nil(t, fn)
@@ -219,7 +225,7 @@ func TestSetterBool(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "true", false, false, nil)
 	require.NoError(t, err)
@@ -242,7 +248,7 @@ func TestSetterPtrBool(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "true", false, false, nil)
 	require.NoError(t, err)
@@ -262,7 +268,7 @@ func TestSetterInt(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -285,7 +291,7 @@ func TestSetterPtrInt(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -305,7 +311,7 @@ func TestSetterInt16(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -321,7 +327,7 @@ func TestSetterPtrInt16(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -337,7 +343,7 @@ func TestSetterUint(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -360,7 +366,7 @@ func TestSetterPtrUint(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -380,7 +386,7 @@ func TestSetterUint16(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -396,7 +402,7 @@ func TestSetterPtrUint16(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1", false, false, nil)
 	require.NoError(t, err)
@@ -412,7 +418,7 @@ func TestSetterFloat(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1.1", false, false, nil)
 	require.NoError(t, err)
@@ -435,7 +441,7 @@ func TestSetterPtrFloat(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "1.1", false, false, nil)
 	require.NoError(t, err)
@@ -455,7 +461,7 @@ func TestSetterString(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -468,7 +474,7 @@ func TestSetterPtrString(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -490,7 +496,7 @@ func TestSetterSliceString(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo,bar", false, false, nil)
 	require.NoError(t, err)
@@ -507,7 +513,7 @@ func TestSetterUnmarshalCSV(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -520,7 +526,7 @@ func TestSetterPtrUnmarshalCSV(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -542,7 +548,7 @@ func TestSetterUnmarshalCSV_Error(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
@@ -554,7 +560,7 @@ func TestSetterPtrUnmarshalCSV_Error(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
@@ -566,7 +572,7 @@ func TestSetterUnmarshalText(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -579,7 +585,7 @@ func TestSetterPtrUnmarshalText(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -601,7 +607,7 @@ func TestSetterUnmarshalText_Errors(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
@@ -613,7 +619,7 @@ func TestSetterPtrUnmarshalText_Errors(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
@@ -625,7 +631,7 @@ func TestSetterUnmarshalQuotedCSV(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -641,7 +647,7 @@ func TestSetterPtrUnmarshalQuotedCSV(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.NoError(t, err)
@@ -663,7 +669,7 @@ func TestSetterUnmarshalQuotedCSV_Error(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
@@ -675,12 +681,106 @@ func TestSetterPtrUnmarshalQuotedCSV_Error(t *testing.T) {
 	}
 	tc := testStruct{}
 	fld := reflect.TypeOf(tc).Field(0)
-	fn, err := buildSetter[testStruct]([]int{0}, fld)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
 	require.NoError(t, err)
 	err = fn(&tc, "foo", false, false, nil)
 	require.Error(t, err)
 }
 
+func TestSetterTime(t *testing.T) {
+	type testStruct struct {
+		Foo time.Time
+	}
+	tc := testStruct{}
+	fld := reflect.TypeOf(tc).Field(0)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
+	require.NoError(t, err)
+	err = fn(&tc, "2025-01-02T03:04:05Z", false, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), tc.Foo)
+
+	err = fn(&tc, "02/01/2025", false, false, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot convert value \"02/01/2025\" to time")
+
+	err = fn(&tc, "", false, false, nil)
+	require.Error(t, err)
+	err = fn(&tc, "", false, true, nil)
+	require.NoError(t, err)
+	require.True(t, tc.Foo.IsZero())
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	fn, err = buildSetter[testStruct]([]int{0}, fld, &fieldOptions{layout: "02/01/2006 15:04", location: loc})
+	require.NoError(t, err)
+	err = fn(&tc, "02/01/2025 10:30", false, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 2, 10, 30, 0, 0, loc), tc.Foo)
+}
+
+func TestSetterPtrTime(t *testing.T) {
+	type testStruct struct {
+		Foo *time.Time
+	}
+	tc := testStruct{}
+	fld := reflect.TypeOf(tc).Field(0)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, &fieldOptions{layout: "2006-01-02"})
+	require.NoError(t, err)
+	err = fn(&tc, "2025-01-02", false, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), *tc.Foo)
+
+	err = fn(&tc, "not a date", false, false, nil)
+	require.Error(t, err)
+
+	err = fn(&tc, "", false, false, nil)
+	require.NoError(t, err)
+	require.Nil(t, tc.Foo)
+}
+
+func TestSetterDuration(t *testing.T) {
+	type testStruct struct {
+		Foo time.Duration
+	}
+	tc := testStruct{}
+	fld := reflect.TypeOf(tc).Field(0)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
+	require.NoError(t, err)
+	err = fn(&tc, "1h30m", false, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, tc.Foo)
+
+	err = fn(&tc, "not a duration", false, false, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "to duration")
+
+	err = fn(&tc, "", false, false, nil)
+	require.Error(t, err)
+	err = fn(&tc, "", false, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), tc.Foo)
+}
+
+func TestSetterPtrDuration(t *testing.T) {
+	type testStruct struct {
+		Foo *time.Duration
+	}
+	tc := testStruct{}
+	fld := reflect.TypeOf(tc).Field(0)
+	fn, err := buildSetter[testStruct]([]int{0}, fld, nil)
+	require.NoError(t, err)
+	err = fn(&tc, "1.5s", false, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, *tc.Foo)
+
+	err = fn(&tc, "not a duration", false, false, nil)
+	require.Error(t, err)
+
+	err = fn(&tc, "", false, false, nil)
+	require.NoError(t, err)
+	require.Nil(t, tc.Foo)
+}
+
 type MyString string
 
 func (my *MyString) UnmarshalCSV(s string, record []string) error {
@@ -0,0 +1,441 @@
+package csvamp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	csvTagOptionLayout       = "layout"
+	csvTagOptionTimeZone     = "tz"
+	csvTagOptionDefault      = "default"
+	csvTagOptionRequired     = "required"
+	csvTagOptionTrim         = "trim"
+	csvTagOptionOptional     = "optional"
+	csvTagOptionSep          = "sep"
+	csvTagOptionDropTrailing = "droptrailing"
+	csvTagOptionOmitEmpty    = "omitempty"
+	csvTagOptionPrefix       = "prefix"
+	csvTagOptionDecimal      = "decimal"
+	csvTagOptionGrouping     = "grouping"
+	csvTagOptionCurrency     = "currency"
+	csvTagOptionParens       = "parens"
+	csvTagOptionPercent      = "percent"
+	csvTagOptionTrue         = "true"
+	csvTagOptionFalse        = "false"
+	csvTagOptionNull         = "null"
+	csvTagOptionEnum         = "enum"
+	csvTagOptionBase         = "base"
+	csvTagOptionPrec         = "prec"
+)
+
+// csvTagOptions are the known csv tag options (and whether each option requires a value)
+var csvTagOptions = map[string]bool{
+	csvTagOptionLayout:       true,
+	csvTagOptionTimeZone:     true,
+	csvTagOptionDefault:      true,
+	csvTagOptionRequired:     false,
+	csvTagOptionTrim:         false,
+	csvTagOptionOptional:     false,
+	csvTagOptionSep:          true,
+	csvTagOptionDropTrailing: false,
+	csvTagOptionOmitEmpty:    false,
+	csvTagOptionPrefix:       false,
+	csvTagOptionDecimal:      true,
+	csvTagOptionGrouping:     true,
+	csvTagOptionCurrency:     true,
+	csvTagOptionParens:       false,
+	csvTagOptionPercent:      false,
+	csvTagOptionTrue:         true,
+	csvTagOptionFalse:        true,
+	csvTagOptionNull:         true,
+	csvTagOptionEnum:         true,
+	csvTagOptionBase:         true,
+	csvTagOptionPrec:         true,
+}
+
+// fieldTag is the parsed csv tag of a struct field
+//
+// the tag is of the form "name,option,option=value,..." - where the name is the csv field index (e.g. "[1]") or header name
+//
+// the name, or an option value, can be enclosed in single quotes (e.g. "layout='Jan 2, 2006'") - within which commas are not treated as separators
+// and a single quote is escaped by doubling it
+type fieldTag struct {
+	name    string
+	options map[string]string
+}
+
+func parseFieldTag(tag string) (fieldTag, error) {
+	parts, err := splitFieldTag(tag)
+	if err != nil {
+		return fieldTag{}, err
+	}
+	result := fieldTag{
+		name:    parts[0],
+		options: make(map[string]string, len(parts)-1),
+	}
+	for _, part := range parts[1:] {
+		k, v, hasValue := strings.Cut(part, "=")
+		k = strings.TrimSpace(k)
+		if requiresValue, ok := csvTagOptions[k]; !ok {
+			return result, fmt.Errorf("unknown csv tag option %q", k)
+		} else if requiresValue && !hasValue {
+			return result, fmt.Errorf("csv tag option %q requires a value", k)
+		} else if !requiresValue && hasValue {
+			return result, fmt.Errorf("csv tag option %q does not take a value", k)
+		} else if _, exists := result.options[k]; exists {
+			return result, fmt.Errorf("duplicate csv tag option %q", k)
+		}
+		result.options[k] = v
+	}
+	return result, result.validate()
+}
+
+func (ft fieldTag) validate() error {
+	if len(ft.options) > 0 {
+		switch ft.name {
+		case csvTagLine, csvTagRaw, csvTagRawData, csvTagExtra, "-":
+			return fmt.Errorf("csv tag options not supported with %q", ft.name)
+		}
+	}
+	for _, opt := range []string{csvTagOptionLayout, csvTagOptionSep, csvTagOptionDecimal, csvTagOptionGrouping, csvTagOptionCurrency} {
+		if v, ok := ft.options[opt]; ok && v == "" {
+			return fmt.Errorf("csv tag option %q cannot be empty", opt)
+		}
+	}
+	_, hasDefault := ft.options[csvTagOptionDefault]
+	_, hasRequired := ft.options[csvTagOptionRequired]
+	_, hasOptional := ft.options[csvTagOptionOptional]
+	if hasDefault && hasRequired {
+		return fmt.Errorf("csv tag options %q and %q cannot be used together", csvTagOptionDefault, csvTagOptionRequired)
+	} else if hasOptional && hasRequired {
+		return fmt.Errorf("csv tag options %q and %q cannot be used together", csvTagOptionOptional, csvTagOptionRequired)
+	}
+	return nil
+}
+
+// splitFieldTag splits the tag into its comma separated parts - honouring single quoted names and option values
+func splitFieldTag(tag string) ([]string, error) {
+	result := make([]string, 0)
+	var sb strings.Builder
+	// a quote is only significant at the start of the name or at the start of an option value...
+	quotable := true
+	hasEquals := false
+	for i := 0; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == '\'' && quotable:
+			closed := false
+			for i++; i < len(tag) && !closed; i++ {
+				if tag[i] != '\'' {
+					sb.WriteByte(tag[i])
+				} else if i+1 < len(tag) && tag[i+1] == '\'' {
+					sb.WriteByte('\'')
+					i++
+				} else {
+					closed = true
+				}
+			}
+			i--
+			if !closed {
+				return nil, errors.New("unterminated quote in csv tag")
+			} else if i+1 < len(tag) && tag[i+1] != ',' {
+				return nil, errors.New("unexpected characters after quote in csv tag")
+			}
+			quotable = false
+		case c == ',':
+			result = append(result, sb.String())
+			sb.Reset()
+			quotable, hasEquals = true, false
+		case c == '=' && !hasEquals && len(result) > 0:
+			sb.WriteByte(c)
+			quotable, hasEquals = true, true
+		default:
+			sb.WriteByte(c)
+			quotable = false
+		}
+	}
+	return append(result, sb.String()), nil
+}
+
+// fieldOptions are the resolved options (from the field csv tag and mapper options) used to build field setters and getters
+type fieldOptions struct {
+	layout       string
+	location     *time.Location
+	defaultValue *string
+	required     bool
+	trim         bool
+	separator    string
+	dropTrailing bool
+	omitEmpty    bool
+	number       *NumberFormat
+	bools        *boolTokens
+	nulls        map[string]bool
+	converters   map[reflect.Type]valueSetter
+	formatters   map[reflect.Type]valueGetter
+	enum         []string
+	enums        map[reflect.Type][]string
+	base         *int
+	prec         uint
+}
+
+func (m *mapper[T]) fieldOptions(fldName string) (*fieldOptions, error) {
+	result := &fieldOptions{
+		layout:     m.timeLayout,
+		location:   m.timeLocation,
+		separator:  m.sliceSeparator,
+		number:     m.numberFormat,
+		bools:      m.boolValues.tokens(),
+		nulls:      m.nullValues,
+		converters: m.converters,
+		formatters: m.formatters,
+		enums:      m.enums,
+	}
+	tag := m.fieldTags[fldName]
+	var t T
+	if err := tag.validateForType(reflect.TypeOf(t).FieldByIndex(m.fieldIndices[fldName]).Type); err != nil {
+		return nil, fmt.Errorf("%w (field name: %q)", err, fldName)
+	}
+	if v, ok := tag.options[csvTagOptionBase]; ok {
+		base, err := strconv.Atoi(v)
+		if err != nil || base == 1 || base < 0 || base > 36 {
+			return nil, fmt.Errorf("invalid csv tag option %s=%q (field name: %q)", csvTagOptionBase, v, fldName)
+		}
+		result.base = &base
+	}
+	if v, ok := tag.options[csvTagOptionPrec]; ok {
+		prec, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || prec == 0 || prec > big.MaxPrec {
+			return nil, fmt.Errorf("invalid csv tag option %s=%q (field name: %q)", csvTagOptionPrec, v, fldName)
+		}
+		result.prec = uint(prec)
+	}
+	if v, ok := tag.options[csvTagOptionEnum]; ok {
+		result.enum = strings.Split(v, "|")
+	}
+	if v, ok := tag.options[csvTagOptionNull]; ok {
+		result.nulls = nullTokens(strings.Split(v, "|"))
+	}
+	trues, hasTrues := tag.options[csvTagOptionTrue]
+	falses, hasFalses := tag.options[csvTagOptionFalse]
+	if hasTrues || hasFalses {
+		bv := BoolValues{}
+		if m.boolValues != nil {
+			bv = *m.boolValues
+		}
+		if hasTrues {
+			bv.True = strings.Split(trues, "|")
+		}
+		if hasFalses {
+			bv.False = strings.Split(falses, "|")
+		}
+		result.bools = bv.tokens()
+	}
+	if tag.hasNumberFormat() {
+		nf := NumberFormat{}
+		if m.numberFormat != nil {
+			nf = *m.numberFormat
+		}
+		if v, ok := tag.options[csvTagOptionDecimal]; ok {
+			nf.DecimalSeparator = v
+		}
+		if v, ok := tag.options[csvTagOptionGrouping]; ok {
+			nf.GroupingSeparator = v
+		}
+		if v, ok := tag.options[csvTagOptionCurrency]; ok {
+			// multiple currency symbols are separated by "|" (e.g. `currency=$|€`)...
+			nf.CurrencySymbols = strings.Split(v, "|")
+		}
+		if _, ok := tag.options[csvTagOptionParens]; ok {
+			nf.ParenthesesNegative = true
+		}
+		if _, ok := tag.options[csvTagOptionPercent]; ok {
+			nf.Percent = true
+		}
+		if err := nf.validate(); err != nil {
+			return nil, fmt.Errorf("%w (field name: %q)", err, fldName)
+		}
+		result.number = &nf
+	}
+	if v, ok := tag.options[csvTagOptionLayout]; ok {
+		result.layout = v
+	}
+	if v, ok := tag.options[csvTagOptionSep]; ok {
+		result.separator = v
+	}
+	if v, ok := tag.options[csvTagOptionTimeZone]; ok {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv tag option %s=%q (field name: %q)", csvTagOptionTimeZone, v, fldName)
+		}
+		result.location = loc
+	}
+	if v, ok := tag.options[csvTagOptionDefault]; ok {
+		result.defaultValue = &v
+	}
+	_, result.required = tag.options[csvTagOptionRequired]
+	_, result.trim = tag.options[csvTagOptionTrim]
+	_, result.dropTrailing = tag.options[csvTagOptionDropTrailing]
+	_, result.omitEmpty = tag.options[csvTagOptionOmitEmpty]
+	return result, nil
+}
+
+// validateForType checks that the csv tag options have an effect for the struct field type (e.g. "layout" is only used by time fields)
+//
+// options of slice, map and pointer fields apply to their elements
+func (ft fieldTag) validateForType(typ reflect.Type) error {
+	et := derefType(typ)
+	if _, ok := ft.options[csvTagOptionSep]; ok && (et.Kind() != reflect.Slice || et.Elem().Kind() == reflect.Uint8) {
+		return fmt.Errorf("csv tag option %q only supported for slice fields", csvTagOptionSep)
+	}
+	if (et.Kind() == reflect.Slice && et.Elem().Kind() != reflect.Uint8) || et.Kind() == reflect.Map {
+		et = derefType(et.Elem())
+	}
+	for _, check := range []struct {
+		options   []string
+		supported bool
+		fields    string
+	}{
+		{[]string{csvTagOptionLayout, csvTagOptionTimeZone}, et == timeType, "time"},
+		{[]string{csvTagOptionBase}, et == bigIntType || isIntKind(et.Kind()), "int, uint or big.Int"},
+		{[]string{csvTagOptionPrec}, et == bigFloatType, "big.Float"},
+		{[]string{csvTagOptionTrue, csvTagOptionFalse}, et.Kind() == reflect.Bool, "bool"},
+		{[]string{csvTagOptionEnum}, et.Kind() != reflect.Slice, "non-[]byte"},
+	} {
+		for _, opt := range check.options {
+			if _, ok := ft.options[opt]; ok && !check.supported {
+				return fmt.Errorf("csv tag option %q only supported for %s fields", opt, check.fields)
+			}
+		}
+	}
+	return nil
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+	return typ
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func (ft fieldTag) hasNumberFormat() bool {
+	for _, opt := range []string{csvTagOptionDecimal, csvTagOptionGrouping, csvTagOptionCurrency, csvTagOptionParens, csvTagOptionPercent} {
+		if _, ok := ft.options[opt]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isNull determines whether the value is a null token
+func (o *fieldOptions) isNull(val string) bool {
+	return o != nil && o.nulls[val]
+}
+
+func nullTokens(tokens []string) map[string]bool {
+	if len(tokens) == 0 {
+		return nil
+	}
+	result := make(map[string]bool, len(tokens))
+	for _, s := range tokens {
+		result[s] = true
+	}
+	return result
+}
+
+func (o *fieldOptions) converter(typ reflect.Type) (vs valueSetter, ok bool) {
+	if o != nil {
+		vs, ok = o.converters[typ]
+	}
+	return vs, ok
+}
+
+func (o *fieldOptions) formatter(typ reflect.Type) (vg valueGetter, ok bool) {
+	if o != nil {
+		vg, ok = o.formatters[typ]
+	}
+	return vg, ok
+}
+
+// enumValues returns the allowed values for the type (the field "enum" tag option takes precedence over values registered for the type)
+func (o *fieldOptions) enumValues(typ reflect.Type) []string {
+	if o == nil {
+		return nil
+	} else if o.enum != nil {
+		return o.enum
+	}
+	return o.enums[typ]
+}
+
+// intBase returns the base for reading (and writing) int and uint fields (nil if not specified) - base 0 reads using Go integer literal prefixes (e.g. "0x1F", "0o17", "0b1010") and writes as base 10
+func (o *fieldOptions) intBase() *int {
+	if o == nil {
+		return nil
+	}
+	return o.base
+}
+
+// floatPrecision returns the precision (in mantissa bits) for reading big.Float fields - zero means the precision is determined by the number of digits in the value
+func (o *fieldOptions) floatPrecision() uint {
+	if o == nil {
+		return 0
+	}
+	return o.prec
+}
+
+func (o *fieldOptions) boolTokens() *boolTokens {
+	if o == nil {
+		return nil
+	}
+	return o.bools
+}
+
+// numberFormat returns the number format (nil if numbers are read and written as per strconv)
+func (o *fieldOptions) numberFormat() *NumberFormat {
+	if o == nil || o.number.isZero() {
+		return nil
+	}
+	return o.number
+}
+
+func (o *fieldOptions) timeLayout() string {
+	if o == nil || o.layout == "" {
+		return time.RFC3339
+	}
+	return o.layout
+}
+
+func (o *fieldOptions) sliceSeparator() string {
+	if o == nil || o.separator == "" {
+		return ","
+	}
+	return o.separator
+}
+
+func (o *fieldOptions) timeLocation() *time.Location {
+	if o == nil || o.location == nil {
+		return time.UTC
+	}
+	return o.location
+}
+
+// formatLocation returns the location that times are converted to when writing (nil means times are written as is)
+func (o *fieldOptions) formatLocation() *time.Location {
+	if o == nil {
+		return nil
+	}
+	return o.location
+}
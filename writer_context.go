@@ -0,0 +1,141 @@
+package csvamp
+
+import (
+	"github.com/go-andiamo/csvamp/csv"
+)
+
+// WriterContext is the interface used to actually write structs as CSV
+//
+// A writer context is obtained from Mapper.Writer or Mapper.WriterContext
+type WriterContext[T any] interface {
+	// Write writes the struct as the next CSV line
+	//
+	// If the header line has not yet been written, it is written before the struct line
+	Write(row T) error
+	// WriteAll writes all the structs as CSV lines and then flushes
+	WriteAll(rows []T) error
+	// Flush writes any buffered data to the underlying io.Writer
+	//
+	// If the header line has not yet been written, it is written before flushing
+	Flush() error
+}
+
+type writerColumn[T any] struct {
+	header string
+	getter func(t *T, record []string) (string, csv.QuotePolicy, error)
+}
+
+// writerField is a mapped struct field written as one or more CSV fields
+type writerField[T any] struct {
+	index  int // 1 based csv field index (zero if written after the fields mapped by index)
+	size   int // number of csv fields of a bounded index range (zero if not)
+	column writerColumn[T]
+	// columns resolves the columns of a slice or map field written to multiple csv fields (by index range, header pattern or header regex) - from the first struct written
+	columns func(first *T) ([]writerColumn[T], func(t *T) error)
+}
+
+type writerContext[T any] struct {
+	writer     *csv.Writer
+	fields     []writerField[T]
+	columns    []writerColumn[T]
+	checks     []func(t *T) error
+	columnsErr error
+}
+
+func newWriterContext[T any](w *csv.Writer, fields []writerField[T], columnsErr error) *writerContext[T] {
+	return &writerContext[T]{
+		writer:     w,
+		fields:     fields,
+		columnsErr: columnsErr,
+	}
+}
+
+// resolveColumns resolves the columns written (and the header, if not already set) - fields mapped by index are written at their index, followed by fields mapped by header
+func (wc *writerContext[T]) resolveColumns(first *T) {
+	maxIndex := 0
+	indexed := make(map[int]writerColumn[T])
+	named := make([]writerColumn[T], 0)
+	for _, fld := range wc.fields {
+		columns := []writerColumn[T]{fld.column}
+		if fld.columns != nil {
+			var check func(t *T) error
+			columns, check = fld.columns(first)
+			wc.checks = append(wc.checks, check)
+		}
+		if fld.index > 0 {
+			for i, col := range columns {
+				indexed[fld.index+i] = col
+			}
+			maxIndex = max(maxIndex, fld.index+max(fld.size, len(columns))-1)
+		} else {
+			named = append(named, columns...)
+		}
+	}
+	wc.columns = make([]writerColumn[T], maxIndex, maxIndex+len(named))
+	for idx, col := range indexed {
+		wc.columns[idx-1] = col
+	}
+	wc.columns = append(wc.columns, named...)
+	if wc.writer.Header == nil {
+		wc.writer.Header = make([]string, len(wc.columns))
+		for i, col := range wc.columns {
+			wc.writer.Header[i] = col.header
+		}
+	}
+}
+
+func (wc *writerContext[T]) Write(row T) error {
+	if wc.columnsErr != nil {
+		return wc.columnsErr
+	}
+	if wc.columns == nil {
+		wc.resolveColumns(&row)
+	}
+	for _, check := range wc.checks {
+		if err := check(&row); err != nil {
+			return err
+		}
+	}
+	record := make([]string, 0, len(wc.columns))
+	quoting := make([]csv.QuotePolicy, len(wc.columns))
+	for i, col := range wc.columns {
+		val := ""
+		if col.getter != nil {
+			var err error
+			if val, quoting[i], err = col.getter(&row, record); err != nil {
+				return err
+			} else if quoting[i] == quoteEmptyValue {
+				quoting[i] = csv.QuoteAll
+				if wc.writer.QuotePolicy == csv.QuoteNever {
+					quoting[i] = csv.QuoteDefault
+				}
+			}
+		}
+		record = append(record, val)
+	}
+	return wc.writer.WriteQuoted(record, quoting)
+}
+
+func (wc *writerContext[T]) WriteAll(rows []T) error {
+	for _, row := range rows {
+		if err := wc.Write(row); err != nil {
+			return err
+		}
+	}
+	return wc.Flush()
+}
+
+func (wc *writerContext[T]) Flush() error {
+	if wc.columnsErr != nil {
+		return wc.columnsErr
+	}
+	if wc.columns == nil {
+		var empty T
+		wc.resolveColumns(&empty)
+	}
+	if err := wc.writer.WriteHeader(); err != nil {
+		return err
+	}
+	wc.writer.Flush()
+	return wc.writer.Error()
+}
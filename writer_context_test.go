@@ -0,0 +1,353 @@
+package csvamp
+
+import (
+	"bytes"
+	"github.com/go-andiamo/csvamp/csv"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterContext_Write(t *testing.T) {
+	type testStruct struct {
+		Line int      `csv:"[line]"`
+		Raw  []string `csv:"[raw]"`
+		Foo  string
+		Bar  int
+		Baz  *bool
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := m.Writer(&buf)
+	b := true
+	err = w.Write(testStruct{Line: 1, Foo: "Aaa", Bar: 1, Baz: &b})
+	require.NoError(t, err)
+	err = w.Write(testStruct{Foo: "B,bb", Bar: 2})
+	require.NoError(t, err)
+	err = w.Flush()
+	require.NoError(t, err)
+	require.Equal(t, "Foo,Bar,Baz\nAaa,1,true\n\"B,bb\",2,\n", buf.String())
+}
+
+func TestWriterContext_WriteAll(t *testing.T) {
+	type Nested struct {
+		Qux string `csv:"qux"`
+	}
+	type Embedded struct {
+		Baz string `csv:"[4]"`
+	}
+	type testStruct struct {
+		Foo    string `csv:"[2]"`
+		Bar    string `csv:"bar"`
+		Nested Nested
+		Embedded
+		Ignored string `csv:"-"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf).WriteAll([]testStruct{
+		{
+			Foo:      "Aaa",
+			Bar:      "Bbb",
+			Nested:   Nested{Qux: "Ccc"},
+			Embedded: Embedded{Baz: "Ddd"},
+			Ignored:  "Eee",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ",Foo,,Baz,bar,qux\n,Aaa,,Ddd,Bbb,Ccc\n", buf.String())
+}
+
+func TestWriterContext_WriteAll_NestedHeaders(t *testing.T) {
+	type Addr struct {
+		Street string
+		City   string
+	}
+	type testStruct struct {
+		Name string
+		Home Addr
+		Work *Addr
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf).WriteAll([]testStruct{
+		{Name: "Bilbo", Home: Addr{Street: "Bag End", City: "Hobbiton"}, Work: &Addr{Street: "1 Main St", City: "Bree"}},
+		{Name: "Frodo"},
+	})
+	require.NoError(t, err)
+	const data = "Name,Home.Street,Home.City,Work.Street,Work.City\nBilbo,Bag End,Hobbiton,1 Main St,Bree\nFrodo,,,,\n"
+	require.Equal(t, data, buf.String())
+
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	require.Equal(t, "Bree", recs[0].Work.City)
+	require.Nil(t, recs[1].Work)
+}
+
+func TestWriterContext_WriteAll_Empty(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar string
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf).WriteAll(nil)
+	require.NoError(t, err)
+	require.Equal(t, "Foo,Bar\n", buf.String())
+}
+
+func TestWriterContext_WithOptions(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar string
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf, csv.Comma(';'), csv.NoHeader(true), nil).WriteAll([]testStruct{{Foo: "Aaa", Bar: "Bbb"}})
+	require.NoError(t, err)
+	require.Equal(t, "Aaa;Bbb\n", buf.String())
+}
+
+func TestWriterContext_Adapted(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar string
+		Baz string `csv:"-"`
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	m2, err := m.Adapt(false, OverrideMappings{
+		{
+			FieldName:    "Baz",
+			CsvFieldName: "baz",
+		},
+		{
+			FieldName:     "Foo",
+			CsvFieldIndex: -1,
+		},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m2.Writer(&buf).WriteAll([]testStruct{{Foo: "Aaa", Bar: "Bbb", Baz: "Ccc"}})
+	require.NoError(t, err)
+	require.Equal(t, ",Bar,baz\n,Bbb,Ccc\n", buf.String())
+}
+
+func TestWriterContext_RoundTrip(t *testing.T) {
+	type testStruct struct {
+		FirstName string `csv:"First name"`
+		LastName  string `csv:"Last name"`
+		Age       int    `csv:"Age"`
+		Address   []string
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	const data = `Address,First name,Last name,Age
+"1 Bagshot Row,Hobbiton",Frodo,Baggins,50
+"2 Bagshot Row,Hobbiton",Samwise,Gamgee,38
+`
+	recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	err = m.Writer(&buf).WriteAll(recs)
+	require.NoError(t, err)
+	require.Equal(t, data, buf.String())
+}
+
+func TestWriterContext_Errors(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar Unmarshalable
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := m.Writer(&buf)
+	err = w.Write(testStruct{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "struct field unsupported type:")
+	require.Contains(t, err.Error(), `(field name: "Bar")`)
+	err = w.Flush()
+	require.Error(t, err)
+	err = w.WriteAll([]testStruct{{}})
+	require.Error(t, err)
+	require.Empty(t, buf.String())
+}
+
+func TestWriterContext_Write_Marshalers(t *testing.T) {
+	type testStruct struct {
+		Foo MyMarshalString
+		Bar *MyMarshalText
+		Baz time.Time
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	text := MyMarshalText("bbb")
+	err = m.Writer(&buf).WriteAll([]testStruct{
+		{Foo: "aaa", Bar: &text, Baz: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Foo: "ccc"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Foo,Bar,Baz\n<aaa>,[bbb],2025-01-02T03:04:05Z\n<ccc>,,0001-01-01T00:00:00Z\n", buf.String())
+}
+
+func TestWriterContext_Write_MarshalerError(t *testing.T) {
+	type testStruct struct {
+		Foo MyBadMarshalString
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf).Write(testStruct{})
+	require.Error(t, err)
+	require.Equal(t, "fooey", err.Error())
+}
+
+func TestWriterContext_Write_Quoting(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar *string
+		Baz MyQuotedMarshalString
+		Qux int
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	empty := ""
+	rows := []testStruct{
+		{Foo: "Aaa", Bar: &empty, Baz: "Bbb", Qux: 1},
+		{Foo: "Ccc", Bar: nil, Baz: "", Qux: 2},
+	}
+	t.Run("Default", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf).WriteAll(rows)
+		require.NoError(t, err)
+		require.Equal(t, "Foo,Bar,Baz,Qux\nAaa,\"\",\"Bbb\",1\nCcc,,,2\n", buf.String())
+	})
+	t.Run("QuoteAll", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf, csv.QuoteAll).WriteAll(rows)
+		require.NoError(t, err)
+		require.Equal(t, "\"Foo\",\"Bar\",\"Baz\",\"Qux\"\n\"Aaa\",\"\",\"Bbb\",\"1\"\n\"Ccc\",\"\",,\"2\"\n", buf.String())
+	})
+	t.Run("QuoteNonNumeric", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf, csv.QuoteNonNumeric, csv.NoHeader(true), csv.LineTerminator("\r\n")).WriteAll(rows)
+		require.NoError(t, err)
+		require.Equal(t, "\"Aaa\",\"\",\"Bbb\",1\r\n\"Ccc\",,,2\r\n", buf.String())
+	})
+	t.Run("Round trip", func(t *testing.T) {
+		const data = "Foo,Bar,Baz,Qux\nAaa,\"\",\"Bbb\",1\nCcc,,,2\n"
+		recs, err := m.Reader(strings.NewReader(data), nil).ReadAll()
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		err = m.Writer(&buf).WriteAll(recs)
+		require.NoError(t, err)
+		require.Equal(t, data, buf.String())
+	})
+}
+
+func TestWriterContext_Write_QuotedMarshaler(t *testing.T) {
+	type testStruct struct {
+		Foo MyQuotedMarshalString
+		Bar *MyQuotedMarshalString
+		Baz MyUnquotedMarshalString
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	bar := MyQuotedMarshalString("Bbb")
+	rows := []testStruct{
+		{Foo: "Aaa", Bar: &bar, Baz: "Ccc"},
+		{Foo: "", Bar: nil, Baz: "D,dd"},
+	}
+	t.Run("Forced", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf, csv.NoHeader(true)).WriteAll(rows)
+		require.NoError(t, err)
+		require.Equal(t, "\"Aaa\",\"Bbb\",Ccc\n,,\"D,dd\"\n", buf.String())
+	})
+	t.Run("Suppressed", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf, csv.NoHeader(true), csv.QuoteAll).WriteAll(rows)
+		require.NoError(t, err)
+		// suppressed quoting still quotes values that require quoting...
+		require.Equal(t, "\"Aaa\",\"Bbb\",Ccc\n,\"\",\"D,dd\"\n", buf.String())
+	})
+	t.Run("Never", func(t *testing.T) {
+		var buf bytes.Buffer
+		err = m.Writer(&buf, csv.NoHeader(true), csv.QuoteNever).WriteAll(rows[:1])
+		require.NoError(t, err)
+		require.Equal(t, "\"Aaa\",\"Bbb\",Ccc\n", buf.String())
+	})
+}
+
+func TestWriterContext_Write_EmptyPointers(t *testing.T) {
+	type testStruct struct {
+		Foo *string
+		Bar *int
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+	empty := ""
+	rows := []testStruct{{Foo: &empty}, {}}
+	var buf bytes.Buffer
+	err = m.Writer(&buf, csv.NoHeader(true)).WriteAll(rows)
+	require.NoError(t, err)
+	require.Equal(t, "\"\",\n,\n", buf.String())
+	buf.Reset()
+	err = m.Writer(&buf, csv.NoHeader(true), csv.QuoteNever).WriteAll(rows)
+	require.NoError(t, err)
+	require.Equal(t, ",\n,\n", buf.String())
+}
+
+type MyUnquotedMarshalString string
+
+func (my *MyUnquotedMarshalString) MarshalQuotedCSV(record []string) (string, bool, error) {
+	return string(*my), false, nil
+}
+
+func TestWriterContext_WithCsvWriter(t *testing.T) {
+	type testStruct struct {
+		Foo string
+		Bar string
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf, csv.Header{"First", "Second"})
+	err = m.WriterContext(w).WriteAll([]testStruct{{Foo: "Aaa", Bar: "Bbb"}})
+	require.NoError(t, err)
+	require.Equal(t, "First,Second\nAaa,Bbb\n", buf.String())
+}
+
+func TestWriterContext_WriteError(t *testing.T) {
+	type testStruct struct {
+		Foo string
+	}
+	m, err := NewMapper[testStruct]()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = m.Writer(&buf, csv.QuoteNever).WriteAll([]testStruct{{Foo: "A,a"}})
+	require.ErrorIs(t, err, csv.ErrQuoteRequired)
+	err = m.Writer(&buf, csv.Comma('"')).Flush()
+	require.Error(t, err)
+}